@@ -0,0 +1,67 @@
+package banji
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnexpectedEvent is reported through an ErrorEvent when a FuncReceiver is routed an Event of a type other than the
+// one its handler accepts.
+var ErrUnexpectedEvent = errors.New("unexpected event type")
+
+// A FuncReceiver is a Receiver that type-checks the Events routed to it and passes them to a handler function.
+type FuncReceiver[T Event] struct {
+	ReceiverEmbed
+	topic   string
+	handler func(context.Context, T) error
+}
+
+// On creates a Receiver that passes every Event routed to topic to handler. Events that are not of type T are not
+// passed to handler; instead, an error wrapping ErrUnexpectedEvent is returned so that it is posted as an ErrorEvent.
+func On[T Event](topic string, handler func(T) error) *FuncReceiver[T] {
+	return OnContext(topic, func(_ context.Context, event T) error {
+		return handler(event)
+	})
+}
+
+// OnContext is like On, except that handler is also passed the context handed to HandleContext. Both return the same
+// kind of Receiver; only the signature of the handler differs.
+func OnContext[T Event](topic string, handler func(context.Context, T) error) *FuncReceiver[T] {
+	return &FuncReceiver[T]{
+		topic:   topic,
+		handler: handler,
+	}
+}
+
+// SubscribeFunc creates a Receiver with On and subscribes it to the engine with the given filters. The returned
+// Receiver serves as the subscription handle and can be passed to Engine.Unsubscribe.
+func SubscribeFunc[T Event](
+	eng *Engine,
+	topic string,
+	handler func(T) error,
+	filters ...Filter,
+) *FuncReceiver[T] {
+	r := On(topic, handler)
+	eng.Subscribe(r, filters...)
+
+	return r
+}
+
+func (r *FuncReceiver[T]) Topic() string {
+	return r.topic
+}
+
+func (r *FuncReceiver[T]) Handle(e Event) error {
+	return r.HandleContext(context.Background(), e)
+}
+
+func (r *FuncReceiver[T]) HandleContext(ctx context.Context, e Event) error {
+	event, ok := e.(T)
+	if !ok {
+		var expected T
+		return fmt.Errorf("%w: receiver for %q expected %T, received %T", ErrUnexpectedEvent, r.topic, expected, e)
+	}
+
+	return r.handler(ctx, event)
+}
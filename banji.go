@@ -2,9 +2,12 @@
 package banji
 
 import (
+	"context"
 	"sync/atomic"
 	"time"
 
+	"github.com/AndrewChon/banji/bus"
+
 	"github.com/google/uuid"
 )
 
@@ -13,15 +16,26 @@ type Component interface {
 	Bootstrap() ([]Receiver, error)
 }
 
-// An Event is any type that can be emitted and routed by the engine.
+// An Event is any type that can be emitted and routed by the engine. Canceling an Event before it is routed prevents
+// it from being handled at all; canceling it while it is handled on a topic with sequential dispatch prevents the
+// remaining Receivers from handling it.
 type Event interface {
 	ID() uuid.UUID
 	Postmark() time.Time
 	Topic() string
+	Context() context.Context
 	Cancel()
 	Canceled() bool
 
-	mark()
+	mark(ctx context.Context)
+}
+
+// A PartitionedEvent is an Event that belongs to a partition, such as a single account or player. Events that share a
+// partition key are handled one at a time, in the order they were posted, while different partitions are still handled
+// concurrently. An empty key means the Event does not belong to any partition.
+type PartitionedEvent interface {
+	Event
+	PartitionKey() string
 }
 
 // A Receiver is any type that can receive and handle Event types.
@@ -34,19 +48,67 @@ type Receiver interface {
 	mark()
 }
 
+// A MultiReceiver is a Receiver that listens to several topics at once. When a Receiver implements MultiReceiver, it is
+// subscribed to every topic returned by Topics in place of the one returned by Topic, and unsubscribing it removes it
+// from all of them at once.
+type MultiReceiver interface {
+	Receiver
+	Topics() []string
+}
+
+// A PrioritizedReceiver is a Receiver that declares the order in which it handles an Event relative to the other
+// Receivers of the same topic. Priority is called once for every topic the Receiver is subscribed to; lower priorities
+// are handled first, and Receivers that do not implement PrioritizedReceiver have a priority of zero. Ordering is only
+// guaranteed for topics with sequential dispatch enabled (see WithSequentialTopics).
+type PrioritizedReceiver interface {
+	Receiver
+	Priority(topic string) int
+}
+
+// A RetryingReceiver is a Receiver with its own retry policy, which takes precedence over any policy configured with
+// WithRetryPolicy for the topics it is subscribed to. Returning nil defers to those policies. Only the failing Receiver
+// handles the Event again; errors wrapped with bus.Permanent are never retried.
+type RetryingReceiver interface {
+	Receiver
+	RetryPolicy() *bus.RetryPolicy
+}
+
+// A ContextReceiver is a Receiver whose handler accepts a context. When a Receiver implements ContextReceiver,
+// HandleContext is called in place of Handle. The context is canceled once the handler times out (see
+// WithHandlerTimeout) or the engine has stopped.
+type ContextReceiver interface {
+	Receiver
+	HandleContext(ctx context.Context, event Event) error
+}
+
+// A TimedReceiver is a Receiver with its own handler timeout, which takes precedence over the one configured with
+// WithHandlerTimeout. A non-positive timeout defers to the default.
+type TimedReceiver interface {
+	Receiver
+	Timeout() time.Duration
+}
+
 // A Bus is an entity that can receive and route Event types to Receiver types.
 type Bus interface {
-	Tick()
-	Subscribe(r Receiver)
+	Tick() bus.TickStats
+	TickGroup(group string) bus.TickStats
+	Subscribe(r Receiver, filters ...bus.Filter[Event])
 	Unsubscribe(r Receiver)
 	Post(event Event, priority uint8)
+	PostContext(ctx context.Context, event Event, priority uint8)
 	Size() int
+	Pending() map[string]int
+	Retrying() int
+	NextRetry(group string) (time.Time, bool)
+	Discard() []Event
+	CircuitState(id uuid.UUID) bus.CircuitState
 }
 
 // EventEmbed contains internal methods required to implement the Event interface.
 type EventEmbed struct {
 	id       uuid.UUID
 	postmark time.Time
+	ctx      context.Context
 	canceled atomic.Bool
 }
 
@@ -58,6 +120,15 @@ func (e *EventEmbed) Postmark() time.Time {
 	return e.postmark
 }
 
+// Context returns the context the Event was posted with (see Engine.PostContext).
+func (e *EventEmbed) Context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+
+	return e.ctx
+}
+
 func (e *EventEmbed) Cancel() {
 	e.canceled.Store(true)
 }
@@ -66,9 +137,10 @@ func (e *EventEmbed) Canceled() bool {
 	return e.canceled.Load()
 }
 
-func (e *EventEmbed) mark() {
+func (e *EventEmbed) mark(ctx context.Context) {
 	e.id = uuid.New()
 	e.postmark = time.Now()
+	e.ctx = ctx
 	e.canceled.Store(false)
 }
 
@@ -1,9 +1,20 @@
 package banji
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/AndrewChon/banji/bus"
+
+	"github.com/google/uuid"
 )
 
+// A broadcast is a built-in Event that is posted regardless of whether any Receiver is interested in it. Broadcasts
+// that go unhandled are not dead-lettered.
+type broadcast interface {
+	broadcast()
+}
+
 /* banji.start */
 
 const StartTopic = "banji.start"
@@ -14,6 +25,8 @@ type StartEvent struct {
 	EventEmbed
 }
 
+func (e *StartEvent) broadcast() {}
+
 func (e *StartEvent) Topic() string {
 	return StartTopic
 }
@@ -29,22 +42,66 @@ type StopEvent struct {
 	EventEmbed
 }
 
+func (e *StopEvent) broadcast() {}
+
 func (e *StopEvent) Topic() string {
 	return StopTopic
 }
 
+// tickTiming describes the timing of a tick. It is shared by PreTickEvent and PostTickEvent.
+type tickTiming struct {
+	schedule string
+	number   uint64
+	delta    time.Duration
+	lag      time.Duration
+	timestep time.Duration
+}
+
+// Schedule returns the name of the schedule the tick belongs to, which is empty for the main engine loop.
+func (t tickTiming) Schedule() string {
+	return t.schedule
+}
+
+// Number returns the number of the tick. Tick numbers start from one and increase by one with every tick of the
+// schedule, including catch-up ticks (see WithFixedTimestep).
+func (t tickTiming) Number() uint64 {
+	return t.number
+}
+
+// Delta returns the time elapsed since the previous tick began, or zero for the first tick. In fixed-timestep mode, it
+// is always the timestep, including for catch-up ticks.
+func (t tickTiming) Delta() time.Duration {
+	return t.delta
+}
+
+// Lag returns how far the engine loop is running behind. Normally, it is how late the tick began compared to when it
+// was due; in fixed-timestep mode, it is the time accumulated towards the next tick that has yet to be simulated.
+func (t tickTiming) Lag() time.Duration {
+	return t.lag
+}
+
+// Timestep returns the configured tick period (1s/TPS) of the schedule, or zero for a tickless engine.
+func (t tickTiming) Timestep() time.Duration {
+	return t.timestep
+}
+
 /* banji.preTick */
 
 const PreTickTopic = "banji.preTick"
 
-// PreTickEvent is an Event posted when a new tick has begun.
+// PreTickEvent is an Event posted when a new tick has begun. A tickless engine only posts it if WithTickEvents is used.
+// The PreTickEvent of a named schedule (see WithSchedule) is posted to PreTickTopic followed by the name of the
+// schedule as an additional segment (e.g. "banji.preTick.physics").
 type PreTickEvent struct {
 	EventEmbed
+	tickTiming
 	tick time.Time
 }
 
+func (e *PreTickEvent) broadcast() {}
+
 func (e *PreTickEvent) Topic() string {
-	return PreTickTopic
+	return scheduleTopic(PreTickTopic, e.schedule)
 }
 
 func (e *PreTickEvent) Tick() time.Time {
@@ -56,28 +113,109 @@ func (e *PreTickEvent) Tick() time.Time {
 const PostTickTopic = "banji.postTick"
 
 // PostTickEvent is an Event posted once the work for a given tick has concluded. Therefore, it does not necessarily
-// mean that a new tick has also begun (you should listen for PreTickEvent instead for such purposes).
+// mean that a new tick has also begun (you should listen for PreTickEvent instead for such purposes). A tickless engine
+// only posts it if WithTickEvents is used. Like PreTickEvent, it is posted to a topic of its own for every named
+// schedule.
 type PostTickEvent struct {
 	EventEmbed
-	tick time.Time
+	tickTiming
+	tick  time.Time
+	stats bus.TickStats
 }
 
+func (e *PostTickEvent) broadcast() {}
+
 func (e *PostTickEvent) Topic() string {
-	return PostTickTopic
+	return scheduleTopic(PostTickTopic, e.schedule)
 }
 
 func (e *PostTickEvent) Tick() time.Time {
 	return e.tick
 }
 
+// Stats returns statistics on the work done by the tick, such as how many Events were carried over to the next tick
+// because its budget was exhausted (see WithTickBudget).
+func (e *PostTickEvent) Stats() bus.TickStats {
+	return e.stats
+}
+
+/* banji.tickOverrun */
+
+const TickOverrunTopic = "banji.tickOverrun"
+
+// TickOverrunEvent is an Event posted when a tick of the engine loop takes longer than the tick period (1s/TPS). The
+// ticks that should have begun in the meantime are dropped rather than run late, unless the engine is in
+// fixed-timestep mode (see WithFixedTimestep), in which case they are caught up on instead.
+type TickOverrunEvent struct {
+	EventEmbed
+	number   uint64
+	duration time.Duration
+	period   time.Duration
+	budget   bus.TickBudget
+	stats    bus.TickStats
+	dropped  uint64
+}
+
+func (e *TickOverrunEvent) broadcast() {}
+
+func (e *TickOverrunEvent) Topic() string {
+	return TickOverrunTopic
+}
+
+// Number returns the number of the tick that overran (see Engine.Ticks).
+func (e *TickOverrunEvent) Number() uint64 {
+	return e.number
+}
+
+// Duration returns how long the tick took.
+func (e *TickOverrunEvent) Duration() time.Duration {
+	return e.duration
+}
+
+// Period returns the tick period the tick overran.
+func (e *TickOverrunEvent) Period() time.Duration {
+	return e.period
+}
+
+// Budget returns the budget of the tick (see WithTickBudget).
+func (e *TickOverrunEvent) Budget() bus.TickBudget {
+	return e.budget
+}
+
+// Processed returns the number of Events the tick dispatched, including retries.
+func (e *TickOverrunEvent) Processed() int {
+	return e.stats.Dispatched + e.stats.Retried
+}
+
+// Slowest returns the slowest handler of the tick.
+func (e *TickOverrunEvent) Slowest() bus.HandlerTiming {
+	return e.stats.Slowest
+}
+
+// Stats returns statistics on the work done by the tick.
+func (e *TickOverrunEvent) Stats() bus.TickStats {
+	return e.stats
+}
+
+// Dropped returns the number of ticks that were dropped because of the overrun.
+func (e *TickOverrunEvent) Dropped() uint64 {
+	return e.dropped
+}
+
 /* banji.error */
 
 const ErrorTopic = "banji.error"
 
-// ErrorEvent is an Event posted when a Receiver's handler method returns an error.
+// ErrorEvent is an Event posted when a Receiver's handler method returns an error. Besides the error itself, it
+// describes the Event that was being handled, the Receiver that failed, and how the attempt went.
 type ErrorEvent struct {
 	EventEmbed
-	err error
+	err      error
+	source   Event
+	receiver Receiver
+	duration time.Duration
+	attempt  int
+	depth    int
 }
 
 func (e *ErrorEvent) Topic() string {
@@ -88,6 +226,98 @@ func (e *ErrorEvent) Error() error {
 	return e.err
 }
 
+// Source returns the Event the Receiver failed to handle.
+func (e *ErrorEvent) Source() Event {
+	return e.source
+}
+
+// SourceTopic returns the topic of the Event the Receiver failed to handle.
+func (e *ErrorEvent) SourceTopic() string {
+	if e.source == nil {
+		return ""
+	}
+
+	return e.source.Topic()
+}
+
+// Receiver returns the Receiver that failed.
+func (e *ErrorEvent) Receiver() Receiver {
+	return e.receiver
+}
+
+// ReceiverID returns the ID of the Receiver that failed.
+func (e *ErrorEvent) ReceiverID() uuid.UUID {
+	if e.receiver == nil {
+		return uuid.Nil
+	}
+
+	return e.receiver.ID()
+}
+
+// ReceiverType returns the name of the concrete type of the Receiver that failed (e.g. "*components.Mailer").
+func (e *ErrorEvent) ReceiverType() string {
+	if e.receiver == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%T", e.receiver)
+}
+
+// Duration returns how long the Receiver's handler ran before failing.
+func (e *ErrorEvent) Duration() time.Duration {
+	return e.duration
+}
+
+// Attempt returns the number of times the Receiver has attempted to handle the Event, starting from one.
+func (e *ErrorEvent) Attempt() int {
+	return e.attempt
+}
+
+// Depth returns how deeply the ErrorEvent is nested: an error raised while handling an ordinary Event has a depth of
+// one, while an error raised while handling an ErrorEvent has a depth of one more than that ErrorEvent.
+func (e *ErrorEvent) Depth() int {
+	return e.depth
+}
+
+/* banji.error.summary */
+
+const ErrorSummaryTopic = "banji.error.summary"
+
+// ErrorSummaryEvent is an Event posted at the end of an error window (see WithErrorWindow) in which ErrorEvent types
+// were suppressed, either because they repeated an error already reported during the window, or because they were
+// nested too deeply (see WithMaxErrorDepth).
+type ErrorSummaryEvent struct {
+	EventEmbed
+	summaries []ErrorSummary
+	window    int
+}
+
+func (e *ErrorSummaryEvent) broadcast() {}
+
+func (e *ErrorSummaryEvent) Topic() string {
+	return ErrorSummaryTopic
+}
+
+// Summaries returns a summary of every error that was suppressed during the window.
+func (e *ErrorSummaryEvent) Summaries() []ErrorSummary {
+	return e.summaries
+}
+
+// Suppressed returns the total number of ErrorEvent types suppressed during the window.
+func (e *ErrorSummaryEvent) Suppressed() int {
+	total := 0
+	for _, summary := range e.summaries {
+		total += summary.Suppressed
+	}
+
+	return total
+}
+
+// Window returns the length of the window in ticks.
+func (e *ErrorSummaryEvent) Window() int {
+	return e.window
+}
+
 /* banji.log */
 
 const LogTopic = "banji.log"
@@ -111,3 +341,77 @@ func (e *LogEvent) Topic() string {
 func (e *LogEvent) Log() *Log {
 	return e.log
 }
+
+/* banji.deadLetter */
+
+const DeadLetterTopic = "banji.deadLetter"
+
+// DeadLetterEvent is an Event posted when another Event could not be delivered, such as when no Receiver is subscribed
+// to its topic. It primarily serves to expose typos in topic strings and wiring mistakes.
+type DeadLetterEvent struct {
+	EventEmbed
+	event  Event
+	reason error
+}
+
+func (e *DeadLetterEvent) broadcast() {}
+
+func (e *DeadLetterEvent) Topic() string {
+	return DeadLetterTopic
+}
+
+// Event returns the Event that could not be delivered.
+func (e *DeadLetterEvent) Event() Event {
+	return e.event
+}
+
+// Reason returns an error describing why the Event could not be delivered.
+func (e *DeadLetterEvent) Reason() error {
+	return e.reason
+}
+
+/* banji.circuit */
+
+const (
+	CircuitOpenTopic     = "banji.circuit.open"
+	CircuitHalfOpenTopic = "banji.circuit.halfOpen"
+	CircuitCloseTopic    = "banji.circuit.close"
+)
+
+// CircuitEvent is an Event posted when the circuit of a Receiver changes state (see WithCircuitBreaker). It is posted
+// to CircuitOpenTopic when the Receiver is quarantined, to CircuitHalfOpenTopic when its cooldown is over and a trial
+// Event is handed to it, and to CircuitCloseTopic once it has recovered.
+type CircuitEvent struct {
+	EventEmbed
+	receiver    Receiver
+	state       bus.CircuitState
+	failureRate float64
+}
+
+func (e *CircuitEvent) broadcast() {}
+
+func (e *CircuitEvent) Topic() string {
+	switch e.state {
+	case bus.CircuitOpen:
+		return CircuitOpenTopic
+	case bus.CircuitHalfOpen:
+		return CircuitHalfOpenTopic
+	}
+
+	return CircuitCloseTopic
+}
+
+// Receiver returns the Receiver whose circuit changed state.
+func (e *CircuitEvent) Receiver() Receiver {
+	return e.receiver
+}
+
+// State returns the state the circuit changed to.
+func (e *CircuitEvent) State() bus.CircuitState {
+	return e.state
+}
+
+// FailureRate returns the failure rate of the Receiver when its circuit changed state.
+func (e *CircuitEvent) FailureRate() float64 {
+	return e.failureRate
+}
@@ -0,0 +1,79 @@
+package bus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// A TickBudget limits how much work a single tick may do. Once a tick has dispatched MaxEvents Emittable types, or
+// has spent MaxDuration dispatching them, the remaining Emittable types are carried over to the next tick in order of
+// priority. A zero field means the corresponding limit does not apply. Retries are not subject to the budget.
+//
+// MaxDuration only bounds the time spent dispatching: a tick still waits for the Emittable types it has dispatched to
+// be handled, and dispatching slows down once every worker is busy.
+type TickBudget struct {
+	MaxEvents   int
+	MaxDuration time.Duration
+}
+
+// TickStats describes the work done by a single tick.
+type TickStats struct {
+	// Group is the name of the queue group that was ticked, and Tick the number of the tick within the group, starting
+	// from one.
+	Group string
+	Tick  uint64
+
+	// Dispatched is the number of queued Emittable types that were dispatched, and Retried the number of Emittable
+	// types that were dispatched again after a Subscriber failed to handle them.
+	Dispatched int
+	Retried    int
+
+	// CarriedOver is the number of Emittable types left queued once the TickBudget was exhausted.
+	CarriedOver int
+
+	// Slowest describes the slowest handler of the tick, if any Emittable was handled.
+	Slowest HandlerTiming
+
+	// Duration is how long the tick took, including the time spent handling the Emittable types it dispatched.
+	Duration time.Duration
+}
+
+// A HandlerTiming describes how long a Subscriber took to handle an Emittable.
+type HandlerTiming struct {
+	Subscriber   any
+	SubscriberID uuid.UUID
+	Topic        string
+	Duration     time.Duration
+}
+
+// exhausted reports whether the TickBudget of a tick that began at start has been exhausted after dispatching n
+// Emittable types.
+func (b *Bus[EM, SU]) exhausted(n int, start time.Time) bool {
+	budget := b.options.TickBudget
+	if budget.MaxEvents > 0 && n >= budget.MaxEvents {
+		return true
+	}
+
+	return budget.MaxDuration > 0 && time.Since(start) >= budget.MaxDuration
+}
+
+// recordTiming records how long a Subscriber took to handle an Emittable, keeping track of the slowest handler of the
+// tick in progress of its queue group.
+func (b *Bus[EM, SU]) recordTiming(env *envelope[EM], s SU, duration time.Duration) {
+	q := env.q
+
+	q.slowestMu.Lock()
+	defer q.slowestMu.Unlock()
+
+	if duration <= q.slowest.Duration {
+		return
+	}
+
+	q.slowest = HandlerTiming{
+		Subscriber:   s,
+		SubscriberID: s.ID(),
+		Topic:        env.em.Topic(),
+		Duration:     duration,
+	}
+}
@@ -3,13 +3,38 @@ package bus
 
 import (
 	"cmp"
+	"context"
+	"errors"
+	"slices"
 	"sync"
+	"time"
 
-	"github.com/AndrewChon/gsync"
 	"github.com/AndrewChon/pqueue"
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrNoSubscribers is passed to the drop handler when an Emittable is posted to a topic no Subscriber matches.
+	ErrNoSubscribers = errors.New("no subscribers matched the topic")
+
+	// ErrFiltered is passed to the reject handler when every Subscriber matching the topic of an Emittable filtered it
+	// out.
+	ErrFiltered = errors.New("every matching subscriber filtered out the emittable")
+
+	// ErrCircuitOpen is passed to the reject handler when an Emittable is not filtered out by every Subscriber
+	// matching its topic, but the circuits of those that would admit it are open.
+	ErrCircuitOpen = errors.New("the circuits of every admitting subscriber are open")
+)
+
+// An envelope carries an Emittable through the bus along with the context it was posted with, the queue of its group
+// and the index of its phase.
+type envelope[EM Emittable] struct {
+	em    EM
+	ctx   context.Context
+	q     *queue[EM]
+	phase int
+}
+
 // A PriorityQueue is any data structure that can store and retrieve elements in order of priority.
 type PriorityQueue[P cmp.Ordered, V any] interface {
 	Push(elem V, priority P)
@@ -27,6 +52,13 @@ type Emittable interface {
 	Canceled() bool
 }
 
+// A PartitionedEmittable is an Emittable that belongs to a partition. Emittable types that share a partition key are
+// handled one at a time, in the order they were posted, while different partitions are still handled concurrently. An
+// empty key means the Emittable does not belong to any partition.
+type PartitionedEmittable interface {
+	PartitionKey() string
+}
+
 // A Subscriber is any type that can receive and handle Emittable types.
 type Subscriber[EM Emittable] interface {
 	ID() uuid.UUID
@@ -34,69 +66,227 @@ type Subscriber[EM Emittable] interface {
 	Handle(em EM) error
 }
 
+// A MultiSubscriber is a Subscriber that listens to several topics at once. When a Subscriber implements
+// MultiSubscriber, the patterns returned by Topics are used in place of the one returned by Topic.
+type MultiSubscriber interface {
+	Topics() []string
+}
+
 type Bus[EM Emittable, SU Subscriber[EM]] struct {
 	options *Options
 
-	bufferQueue  PriorityQueue[uint8, EM]
-	workingQueue PriorityQueue[uint8, EM]
+	// queues holds the queue of every queue group, while groups and phases cache the queue and the phase each
+	// concrete topic is assigned to.
+	queues map[string]*queue[EM]
+	groups *topicCache[*queue[EM]]
+	phases *topicCache[int]
 
 	wp *workerPool
 
-	subscribeQueue   *pqueue.CircularBuffer[SU]
+	subscribeQueue   *pqueue.CircularBuffer[*subscription[EM, SU]]
 	unsubscribeQueue *pqueue.CircularBuffer[SU]
-	subscribers      gsync.Map[string, []SU]
 
-	bufferQueueMu      sync.Mutex
+	// subscribers indexes every subscription by its topic patterns, while registry records the subscription of each
+	// subscriber. routes caches the subscriptions matched by concrete topics that have any, and is cleared whenever
+	// the subscriptions change.
+	subscribers *topicTrie[binding[EM, SU]]
+	registry    map[uuid.UUID]*subscription[EM, SU]
+	routes      *topicCache[*route[EM, SU]]
+
+	// retries holds the Emittable types waiting to be handled again.
+	retries []*retry[EM, SU]
+
+	retriesMu          sync.Mutex
 	subscribeQueueMu   sync.Mutex
 	unsubscribeQueueMu sync.Mutex
+	subscribersMu      sync.RWMutex
 }
 
 func NewBus[EM Emittable, SU Subscriber[EM]](opts ...Option) *Bus[EM, SU] {
 	options := NewOptions(opts...)
 	b := &Bus[EM, SU]{
 		options:          options,
-		bufferQueue:      pqueue.NewPairing[uint8, EM](),
-		workingQueue:     pqueue.NewPairing[uint8, EM](),
-		subscribeQueue:   pqueue.NewCircularBuffer[SU](),
+		queues:           make(map[string]*queue[EM]),
+		subscribeQueue:   pqueue.NewCircularBuffer[*subscription[EM, SU]](),
 		unsubscribeQueue: pqueue.NewCircularBuffer[SU](),
+		subscribers:      newTopicTrie[binding[EM, SU]](),
+		registry:         make(map[uuid.UUID]*subscription[EM, SU]),
+		routes:           newTopicCache[*route[EM, SU]](options.TopicCacheSize),
+		groups:           newTopicCache[*queue[EM]](options.TopicCacheSize),
+		phases:           newTopicCache[int](options.TopicCacheSize),
 		wp:               newWorkerPool(options.Demuxers),
 	}
 
+	// Every queue has a stage for the default phase, followed by one for each Phase.
+	phases := len(options.Phases) + 1
+
+	b.queues[DefaultGroup] = newQueue[EM](DefaultGroup, phases)
+	for _, group := range options.QueueGroups {
+		b.queues[group.Name] = newQueue[EM](group.Name, phases)
+	}
+
 	return b
 }
 
-func (b *Bus[EM, SU]) Tick() {
+// Tick ticks the default queue group (see TickGroup).
+func (b *Bus[EM, SU]) Tick() TickStats {
+	return b.TickGroup(DefaultGroup)
+}
+
+// TickGroup dispatches the Emittable types of a queue group that are due, phase by phase (see Phase) and in order of
+// priority within each phase, and waits for them to be handled. If a TickBudget is set, the Emittable types left over
+// once it is exhausted stay queued, in order of priority, for the next tick of the group. Different groups can be
+// ticked concurrently, while ticks of the same group never overlap. TickGroup returns statistics on the work it did.
+func (b *Bus[EM, SU]) TickGroup(group string) TickStats {
+	q, ok := b.queues[group]
+	if !ok {
+		return TickStats{
+			Group: group,
+		}
+	}
+
+	q.tickMu.Lock()
+	defer q.tickMu.Unlock()
+
+	start := time.Now()
+	stats := TickStats{
+		Group: group,
+		Tick:  q.ticks.Add(1),
+	}
+
 	b.updateSubscribers()
 
-	b.bufferQueueMu.Lock()
-	b.workingQueue.(*pqueue.Pairing[uint8, EM]).Meld(b.bufferQueue.(*pqueue.Pairing[uint8, EM]))
-	b.bufferQueueMu.Unlock()
+	// Phases run one after the other, each waiting for every handler of the previous one to complete, so that
+	// Emittable types posted during a phase to a later phase are dispatched within the same tick.
+	retries := b.dueRetries(q)
+	for phase, st := range q.stages {
+		b.dispatchStage(q, st, phase, retries, start, &stats)
+		q.wg.Wait()
+	}
+
+	for _, st := range q.stages {
+		stats.CarriedOver += st.working.Size()
+	}
+
+	q.slowestMu.Lock()
+	stats.Slowest = q.slowest
+	q.slowest = HandlerTiming{}
+	q.slowestMu.Unlock()
+
+	stats.Duration = time.Since(start)
+	return stats
+}
+
+// dispatchStage dispatches the due retries and queued Emittable types of a phase, within the TickBudget of a tick that
+// began at start.
+func (b *Bus[EM, SU]) dispatchStage(
+	q *queue[EM],
+	st *stage[EM],
+	phase int,
+	retries []*retry[EM, SU],
+	start time.Time,
+	stats *TickStats,
+) {
+	// Emittable types that belong to a partition are collected so that each partition can be handled by a single task,
+	// starting with the retries of the partition.
+	var keys []string
+	partitions := make(map[string][]func())
+	collect := func(key string, task func()) {
+		if _, ok := partitions[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		partitions[key] = append(partitions[key], task)
+	}
+
+	for _, r := range retries {
+		if r.env.phase != phase {
+			continue
+		}
+
+		// A retry that can no longer be made is given up on and reported with the error of the last attempt, while
+		// a retry held back by an open circuit waits for the circuit to let it through.
+		if r.env.em.Canceled() || !b.subscribed(r.sub) {
+			b.fail(r.env, r.failure)
+			continue
+		}
+
+		if !b.allowCircuit(r.sub) {
+			b.holdRetry(r)
+			continue
+		}
+
+		stats.Retried++
+
+		task := func() { b.handlingAgent(r.env, r.sub, r.failure.Attempt+1) }
+		if key := partitionKey(r.env.em); key != "" {
+			collect(key, task)
+			continue
+		}
+
+		b.wp.post(&q.wg, task)
+	}
+
+	q.meld(st)
+
+	dispatched := make(map[string]int)
+
+	for !b.exhausted(stats.Dispatched, start) {
+		env, ok := st.working.Pop()
+		if !ok {
+			break
+		}
+
+		stats.Dispatched++
+
+		key := partitionKey(env.em)
+		if key == "" {
+			dispatched[env.em.Topic()]++
+			b.demux(env)
+			continue
+		}
 
-	for em, ok := b.workingQueue.Pop(); ok; em, ok = b.workingQueue.Pop() {
-		b.demux(em)
+		env = q.nextInLane(st, key, env)
+		dispatched[env.em.Topic()]++
+		collect(key, func() { b.demuxInline(env) })
 	}
 
-	b.wp.wait()
+	q.dispatched(dispatched)
+
+	for _, key := range keys {
+		tasks := partitions[key]
+		b.wp.post(&q.wg, func() {
+			for _, task := range tasks {
+				task()
+			}
+		})
+	}
 }
 
-func (b *Bus[EM, SU]) Subscribe(s SU) {
-	topic := s.Topic()
-	if topic == "" {
+// Subscribe registers a Subscriber under the pattern returned by its Topic method, or under every pattern returned by
+// its Topics method if it implements MultiSubscriber. Patterns are dot-separated and may contain SingleWildcard and
+// MultiWildcard segments. Malformed patterns are ignored.
+//
+// The Subscriber only handles the Emittable types accepted by every one of filters, as well as by its own Filter method
+// if it implements FilteredSubscriber.
+func (b *Bus[EM, SU]) Subscribe(s SU, filters ...Filter[EM]) {
+	sub := newSubscription(s, filters)
+	if len(sub.patterns) == 0 {
 		return
 	}
 
+	if b.options.CircuitBreaker != nil {
+		sub.circuit = newCircuit(b.options.CircuitBreaker.Window)
+	}
+
 	b.subscribeQueueMu.Lock()
 	defer b.subscribeQueueMu.Unlock()
 
-	b.subscribeQueue.Push(s)
+	b.subscribeQueue.Push(sub)
 }
 
+// Unsubscribe unregisters a Subscriber from every pattern it was subscribed under.
 func (b *Bus[EM, SU]) Unsubscribe(s SU) {
-	topic := s.Topic()
-	if topic == "" {
-		return
-	}
-
 	b.unsubscribeQueueMu.Lock()
 	defer b.unsubscribeQueueMu.Unlock()
 
@@ -104,39 +294,255 @@ func (b *Bus[EM, SU]) Unsubscribe(s SU) {
 }
 
 func (b *Bus[EM, SU]) Post(em EM, priority uint8) {
-	b.bufferQueueMu.Lock()
-	b.bufferQueue.Push(em, priority)
-	b.bufferQueueMu.Unlock()
+	b.PostContext(context.Background(), em, priority)
+}
+
+// PostContext posts an Emittable along with a context. The context is handed to every ContextSubscriber that handles
+// the Emittable, and the Emittable is dropped instead of being handled if the context is done by the time it is
+// dequeued. Emittable types posted by the bus on behalf of the Emittable, such as those built by the error builder,
+// inherit the context's values, but not its cancellation. Emittable types posted with the context handed to a
+// ContextSubscriber carry the context returned by Inherit instead.
+func (b *Bus[EM, SU]) PostContext(ctx context.Context, em EM, priority uint8) {
+	env := &envelope[EM]{
+		em:    em,
+		ctx:   Inherit(ctx),
+		q:     b.queueOf(em.Topic()),
+		phase: b.phaseOf(em.Topic()),
+	}
+
+	env.q.push(env, priority)
+
+	b.options.PostHook(em)
 }
 
 func (b *Bus[EM, SU]) Size() int {
-	return b.bufferQueue.Size() + b.workingQueue.Size()
+	size := 0
+	for _, q := range b.queues {
+		size += q.size()
+	}
+
+	return size
+}
+
+// Pending returns the number of Emittable types waiting to be dispatched, per topic, including those carried over
+// from a previous tick. Emittable types waiting to be retried are not included (see Retrying).
+func (b *Bus[EM, SU]) Pending() map[string]int {
+	pending := make(map[string]int)
+	for _, q := range b.queues {
+		q.bufferMu.Lock()
+		for topic, n := range q.pending {
+			pending[topic] += n
+		}
+		q.bufferMu.Unlock()
+	}
+
+	return pending
+}
+
+// Discard removes every Emittable that is waiting to be handled, including those waiting to be retried, and returns
+// those that have not been canceled. It must not be called while a tick is in progress.
+func (b *Bus[EM, SU]) Discard() []EM {
+	var envs []*envelope[EM]
+	for _, q := range b.queues {
+		envs = append(envs, q.discard()...)
+	}
+
+	b.retriesMu.Lock()
+	for _, r := range b.retries {
+		envs = append(envs, r.env)
+	}
+
+	clear(b.retries)
+	b.retries = b.retries[:0]
+	b.retriesMu.Unlock()
+
+	var discarded []EM
+	for _, env := range envs {
+		if !env.em.Canceled() {
+			discarded = append(discarded, env.em)
+		}
+	}
+
+	return discarded
+}
+
+func (b *Bus[EM, SU]) demux(env *envelope[EM]) {
+	rt := b.resolve(env)
+	if rt == nil {
+		return
+	}
+
+	if rt.sequential {
+		b.wp.post(&env.q.wg, func() { b.chain(env, rt.bindings) })
+		return
+	}
+
+	var rejected rejection
+	for _, bd := range rt.bindings {
+		if !rejected.record(b.admit(env.em, bd.sub)) {
+			continue
+		}
+
+		b.wp.post(&env.q.wg, func() { b.handlingAgent(env, bd.sub, 1) })
+	}
+
+	if !rejected.admitted {
+		b.options.RejectHandler(env.em, rejected.reason)
+	}
 }
 
-func (b *Bus[EM, SU]) demux(em EM) {
-	if em.Topic() == "" {
+// resolve returns the route an Emittable should be handled with, or nil if it should not be handled at all.
+func (b *Bus[EM, SU]) resolve(env *envelope[EM]) *route[EM, SU] {
+	em := env.em
+	if em.Canceled() {
+		return nil
+	}
+
+	if err := env.ctx.Err(); err != nil {
+		b.options.DropHandler(em, err)
+		return nil
+	}
+
+	var rt *route[EM, SU]
+	if em.Topic() != "" {
+		rt = b.route(em.Topic())
+	}
+
+	if rt == nil || len(rt.bindings) == 0 {
+		b.options.DropHandler(em, ErrNoSubscribers)
+		return nil
+	}
+
+	return rt
+}
+
+// demuxInline handles an Emittable with every matching subscription on the calling goroutine.
+func (b *Bus[EM, SU]) demuxInline(env *envelope[EM]) {
+	rt := b.resolve(env)
+	if rt == nil {
 		return
 	}
 
-	subs, loaded := b.subscribers.Load(em.Topic())
-	if !loaded {
+	if rt.sequential {
+		b.chain(env, rt.bindings)
 		return
 	}
 
-	for _, s := range subs {
-		b.wp.post(func() { b.handlingAgent(em, s) })
+	var rejected rejection
+	for _, bd := range rt.bindings {
+		if rejected.record(b.admit(env.em, bd.sub)) {
+			b.handlingAgent(env, bd.sub, 1)
+		}
+	}
+
+	if !rejected.admitted {
+		b.options.RejectHandler(env.em, rejected.reason)
+	}
+}
+
+// chain handles an Emittable with each of bindings in order, stopping as soon as it is canceled.
+func (b *Bus[EM, SU]) chain(env *envelope[EM], bindings []binding[EM, SU]) {
+	var rejected rejection
+	for _, bd := range bindings {
+		if env.em.Canceled() {
+			return
+		}
+
+		if !rejected.record(b.admit(env.em, bd.sub)) {
+			continue
+		}
+
+		b.handlingAgent(env, bd.sub, 1)
+	}
+
+	if !rejected.admitted {
+		b.options.RejectHandler(env.em, rejected.reason)
+	}
+}
+
+// route returns the route of topic, which consists of every subscription with a pattern that matches it.
+func (b *Bus[EM, SU]) route(topic string) *route[EM, SU] {
+	if rt, ok := b.routes.Load(topic); ok {
+		return rt
+	}
+
+	// The read lock is held while storing the route so that it cannot race with the cache being cleared.
+	b.subscribersMu.RLock()
+	defer b.subscribersMu.RUnlock()
+
+	rt := &route[EM, SU]{
+		sequential: slices.ContainsFunc(b.options.Sequential, func(pattern string) bool {
+			return Match(pattern, topic)
+		}),
+	}
+
+	// A subscription registered under several matching patterns must only receive the emittable once, at the
+	// earliest of its priorities.
+	indices := make(map[*subscription[EM, SU]]int)
+	b.subscribers.match(topic, func(bd binding[EM, SU]) {
+		i, seen := indices[bd.sub]
+		if !seen {
+			indices[bd.sub] = len(rt.bindings)
+			rt.bindings = append(rt.bindings, bd)
+			return
+		}
+
+		if bd.priority < rt.bindings[i].priority {
+			rt.bindings[i] = bd
+		}
+	})
+
+	slices.SortStableFunc(rt.bindings, func(x, y binding[EM, SU]) int {
+		return cmp.Compare(x.priority, y.priority)
+	})
+
+	// Routes without bindings are not cached, since topics nobody listens to would otherwise fill the cache.
+	if len(rt.bindings) > 0 {
+		b.routes.Store(topic, rt)
 	}
+
+	return rt
 }
 
-func (b *Bus[EM, SU]) handlingAgent(em EM, s SU) {
-	err := s.Handle(em)
+// handlingAgent handles an Emittable with the Subscriber of sub. If it fails, the Emittable is either scheduled to be
+// handled again according to the applicable RetryPolicy, or reported through the error builder.
+func (b *Bus[EM, SU]) handlingAgent(env *envelope[EM], sub *subscription[EM, SU], attempt int) {
+	em, s := env.em, sub.s
+
+	start := time.Now()
+	err := b.invoke(env.ctx, em, s)
+	duration := time.Since(start)
+
+	b.recordCircuit(sub, err != nil)
+	b.recordTiming(env, s, duration)
+
 	if err == nil {
 		return
 	}
 
-	errEm := b.options.ErrorBuilder(err)
+	failure := Failure{
+		Err:          err,
+		Emittable:    em,
+		Subscriber:   s,
+		SubscriberID: s.ID(),
+		Topic:        em.Topic(),
+		Duration:     duration,
+		Attempt:      attempt,
+	}
+
+	if policy := b.retryPolicy(em, s); policy.allows(err, attempt) {
+		b.scheduleRetry(env, sub, policy, failure)
+		return
+	}
+
+	b.fail(env, failure)
+}
+
+// fail reports a failure to handle an Emittable through the error builder.
+func (b *Bus[EM, SU]) fail(env *envelope[EM], failure Failure) {
+	errEm := b.options.ErrorBuilder(failure)
 	if errTyped, ok := errEm.(EM); ok {
-		b.Post(errTyped, 0)
+		b.PostContext(context.WithoutCancel(env.ctx), errTyped, 0)
 	}
 }
 
@@ -146,42 +552,108 @@ func (b *Bus[EM, SU]) updateSubscribers() {
 	defer b.unsubscribeQueueMu.Unlock()
 	defer b.subscribeQueueMu.Unlock()
 
-	for s, ok := b.unsubscribeQueue.Pop(); ok; s, ok = b.unsubscribeQueue.Pop() {
-		if i, found := b.findSubscriber(s); found {
-			subs, _ := b.subscribers.Load(s.Topic())
-			subs = append(subs[:i], subs[i+1:]...)
-			b.subscribers.Store(s.Topic(), subs)
-		}
+	if b.unsubscribeQueue.Size() == 0 && b.subscribeQueue.Size() == 0 {
+		return
 	}
 
-	for s, ok := b.subscribeQueue.Pop(); ok; s, ok = b.subscribeQueue.Pop() {
-		actual, loaded := b.subscribers.Load(s.Topic())
-		if !loaded {
-			b.subscribers.Store(s.Topic(), []SU{s})
+	b.subscribersMu.Lock()
+	defer b.subscribersMu.Unlock()
+
+	for s, ok := b.unsubscribeQueue.Pop(); ok; s, ok = b.unsubscribeQueue.Pop() {
+		sub, found := b.registry[s.ID()]
+		if !found {
 			continue
 		}
 
+		for _, pattern := range sub.patterns {
+			b.subscribers.remove(pattern, func(bd binding[EM, SU]) bool { return bd.sub == sub })
+		}
+
+		delete(b.registry, s.ID())
+	}
+
+	for sub, ok := b.subscribeQueue.Pop(); ok; sub, ok = b.subscribeQueue.Pop() {
 		// Do not allow duplicate subscribers.
-		if _, found := b.findSubscriber(s); found {
+		if _, found := b.registry[sub.s.ID()]; found {
 			continue
 		}
 
-		actual = append(actual, s)
-		b.subscribers.Store(s.Topic(), actual)
+		for _, bd := range sub.bindings() {
+			b.subscribers.insert(bd.pattern, bd)
+		}
+
+		b.registry[sub.s.ID()] = sub
 	}
+
+	b.routes.Clear()
 }
 
-func (b *Bus[EM, SU]) findSubscriber(s SU) (index int, found bool) {
-	subs, loaded := b.subscribers.Load(s.Topic())
-	if !loaded {
-		return 0, false
+// subscribed reports whether sub is still registered.
+func (b *Bus[EM, SU]) subscribed(sub *subscription[EM, SU]) bool {
+	b.subscribersMu.RLock()
+	defer b.subscribersMu.RUnlock()
+
+	return b.registry[sub.s.ID()] == sub
+}
+
+// admit returns nil if em should be handed to sub, taking its filter and circuit into account, or the reason it should
+// not.
+func (b *Bus[EM, SU]) admit(em EM, sub *subscription[EM, SU]) error {
+	if !sub.accepts(em) {
+		return ErrFiltered
+	}
+
+	if !b.allowCircuit(sub) {
+		return ErrCircuitOpen
+	}
+
+	return nil
+}
+
+// A rejection tracks whether any of the subscriptions an Emittable is routed to admitted it, and otherwise why it was
+// rejected. ErrCircuitOpen takes precedence over ErrFiltered, since the Emittable would have been handled if not for
+// the open circuits.
+type rejection struct {
+	admitted bool
+	reason   error
+}
+
+// record records the result of admit and reports whether the Emittable was admitted.
+func (r *rejection) record(err error) bool {
+	if err == nil {
+		r.admitted = true
+		return true
+	}
+
+	if r.reason == nil || errors.Is(err, ErrCircuitOpen) {
+		r.reason = err
 	}
 
-	for i, x := range subs {
-		if x.ID() == s.ID() {
-			return i, true
+	return false
+}
+
+// patternsOf returns the distinct, well-formed patterns a Subscriber should be registered under.
+func patternsOf[EM Emittable, SU Subscriber[EM]](s SU) []string {
+	candidates := []string{s.Topic()}
+	if ms, ok := any(s).(MultiSubscriber); ok {
+		candidates = ms.Topics()
+	}
+
+	var patterns []string
+	for _, pattern := range candidates {
+		if ValidPattern(pattern) && !slices.Contains(patterns, pattern) {
+			patterns = append(patterns, pattern)
 		}
 	}
 
-	return 0, false
+	return patterns
+}
+
+// partitionKey returns the partition key of em, or an empty string if it does not belong to a partition.
+func partitionKey[EM Emittable](em EM) string {
+	if pe, ok := any(em).(PartitionedEmittable); ok {
+		return pe.PartitionKey()
+	}
+
+	return ""
 }
@@ -0,0 +1,57 @@
+package bus
+
+import (
+	"sync"
+)
+
+// DefaultTopicCacheSize is the number of concrete topics whose routing is cached by default.
+const DefaultTopicCacheSize = 4096
+
+// A topicCache caches a value per concrete topic. Topics are often built from identifiers (e.g. "orders.<id>.created"),
+// so the cache holds at most size entries and evicts an arbitrary one whenever it is full. A non-positive size
+// disables caching.
+type topicCache[V any] struct {
+	size    int
+	entries map[string]V
+	mu      sync.RWMutex
+}
+
+func newTopicCache[V any](size int) *topicCache[V] {
+	return &topicCache[V]{
+		size:    size,
+		entries: make(map[string]V),
+	}
+}
+
+func (c *topicCache[V]) Load(topic string) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	v, ok := c.entries[topic]
+	return v, ok
+}
+
+func (c *topicCache[V]) Store(topic string, v V) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[topic]; !ok && len(c.entries) >= c.size {
+		for evicted := range c.entries {
+			delete(c.entries, evicted)
+			break
+		}
+	}
+
+	c.entries[topic] = v
+}
+
+func (c *topicCache[V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+}
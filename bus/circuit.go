@@ -0,0 +1,243 @@
+package bus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// A CircuitState is the state of the circuit between the bus and a Subscriber.
+type CircuitState int8
+
+const (
+	// CircuitClosed means the Subscriber is healthy and is handed every Emittable routed to it.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen means the Subscriber has failed too often and is quarantined until its cooldown is over.
+	CircuitOpen
+
+	// CircuitHalfOpen means the Subscriber's cooldown is over and a single trial Emittable decides whether the circuit
+	// closes or opens again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+// DefaultCircuitThreshold is the failure rate at which a circuit opens when a CircuitBreaker does not set a valid one.
+const DefaultCircuitThreshold = 0.5
+
+// A CircuitBreaker configures how the bus quarantines Subscribers that fail too often.
+type CircuitBreaker struct {
+	// Window is the number of most recent handling attempts the failure rate of a Subscriber is computed over. A
+	// circuit cannot open before Window attempts have been made.
+	Window int
+
+	// Threshold is the failure rate, greater than zero and at most one, at which the circuit opens. Values outside of
+	// that range are replaced with DefaultCircuitThreshold.
+	Threshold float64
+
+	// Cooldown is how long an open circuit stays open before it half-opens.
+	Cooldown time.Duration
+}
+
+// A CircuitChange describes a transition of the circuit between the bus and a Subscriber. It is passed to the circuit
+// builder so that the transition can be announced.
+type CircuitChange struct {
+	Subscriber   any
+	SubscriberID uuid.UUID
+	State        CircuitState
+	FailureRate  float64
+}
+
+// A circuit tracks the recent outcomes of a subscription's handling attempts.
+type circuit struct {
+	mu       sync.Mutex
+	state    CircuitState
+	outcomes []bool // A ring of the most recent outcomes, where true is a failure.
+	next     int
+	samples  int
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func newCircuit(window int) *circuit {
+	return &circuit{
+		outcomes: make([]bool, max(window, 1)),
+	}
+}
+
+// allow reports whether an Emittable may be handed to the subscription, and whether the circuit changed state in order
+// to allow it.
+func (c *circuit) allow(cb *CircuitBreaker, now time.Time) (allowed bool, changed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case CircuitOpen:
+		if now.Sub(c.openedAt) < cb.Cooldown {
+			return false, false
+		}
+
+		c.state = CircuitHalfOpen
+		c.trial = true
+		return true, true
+	case CircuitHalfOpen:
+		// Only the trial Emittable is allowed through until it has been handled.
+		if c.trial {
+			return false, false
+		}
+
+		c.trial = true
+		return true, false
+	}
+
+	return true, false
+}
+
+// record records the outcome of a handling attempt and reports whether the circuit changed state as a result.
+func (c *circuit) record(cb *CircuitBreaker, failed bool, now time.Time) (changed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == CircuitHalfOpen {
+		c.trial = false
+
+		if failed {
+			c.state = CircuitOpen
+			c.openedAt = now
+			return true
+		}
+
+		c.state = CircuitClosed
+		c.reset()
+		return true
+	}
+
+	// Outcomes of attempts that were already in flight when the circuit opened are ignored.
+	if c.state == CircuitOpen {
+		return false
+	}
+
+	if c.samples == len(c.outcomes) && c.outcomes[c.next] {
+		c.failures--
+	}
+
+	c.outcomes[c.next] = failed
+	c.next = (c.next + 1) % len(c.outcomes)
+	c.samples = min(c.samples+1, len(c.outcomes))
+
+	if failed {
+		c.failures++
+	}
+
+	if c.samples < len(c.outcomes) || c.failures == 0 || c.failureRate() < cb.Threshold {
+		return false
+	}
+
+	c.state = CircuitOpen
+	c.openedAt = now
+	return true
+}
+
+// allowedAt returns the earliest time at which an open circuit lets an Emittable through, which is the end of its
+// cooldown. Circuits that are not open return the zero time.
+func (c *circuit) allowedAt(cb *CircuitBreaker) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state != CircuitOpen {
+		return time.Time{}
+	}
+
+	return c.openedAt.Add(cb.Cooldown)
+}
+
+func (c *circuit) reset() {
+	clear(c.outcomes)
+	c.next = 0
+	c.samples = 0
+	c.failures = 0
+}
+
+func (c *circuit) failureRate() float64 {
+	if c.samples == 0 {
+		return 0
+	}
+
+	return float64(c.failures) / float64(c.samples)
+}
+
+func (c *circuit) change() (CircuitState, float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state, c.failureRate()
+}
+
+// CircuitState returns the state of the circuit between the bus and the Subscriber with the given ID. Subscribers that
+// are not subscribed, or that are subscribed to a bus without a CircuitBreaker, are always closed.
+func (b *Bus[EM, SU]) CircuitState(id uuid.UUID) CircuitState {
+	b.subscribersMu.RLock()
+	sub, ok := b.registry[id]
+	b.subscribersMu.RUnlock()
+
+	if !ok || sub.circuit == nil {
+		return CircuitClosed
+	}
+
+	state, _ := sub.circuit.change()
+	return state
+}
+
+// allowCircuit reports whether the circuit of sub allows an Emittable through.
+func (b *Bus[EM, SU]) allowCircuit(sub *subscription[EM, SU]) bool {
+	if sub.circuit == nil {
+		return true
+	}
+
+	allowed, changed := sub.circuit.allow(b.options.CircuitBreaker, time.Now())
+	if changed {
+		b.announceCircuit(sub)
+	}
+
+	return allowed
+}
+
+// recordCircuit records the outcome of a handling attempt made by sub.
+func (b *Bus[EM, SU]) recordCircuit(sub *subscription[EM, SU], failed bool) {
+	if sub.circuit == nil {
+		return
+	}
+
+	if sub.circuit.record(b.options.CircuitBreaker, failed, time.Now()) {
+		b.announceCircuit(sub)
+	}
+}
+
+func (b *Bus[EM, SU]) announceCircuit(sub *subscription[EM, SU]) {
+	state, failureRate := sub.circuit.change()
+
+	em := b.options.CircuitBuilder(CircuitChange{
+		Subscriber:   sub.s,
+		SubscriberID: sub.s.ID(),
+		State:        state,
+		FailureRate:  failureRate,
+	})
+
+	if typed, ok := em.(EM); ok {
+		b.Post(typed, 0)
+	}
+}
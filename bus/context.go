@@ -0,0 +1,129 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrHandlerTimeout is reported through the error path when a Subscriber's handler does not return before its
+	// timeout.
+	ErrHandlerTimeout = errors.New("handler timed out")
+)
+
+// A ContextSubscriber is a Subscriber whose handler accepts a context. When a Subscriber implements ContextSubscriber,
+// HandleContext is called in place of Handle. The context is canceled once the handler times out or the bus's context
+// is canceled.
+type ContextSubscriber[EM Emittable] interface {
+	HandleContext(ctx context.Context, em EM) error
+}
+
+// A TimedSubscriber is a Subscriber with its own handler timeout, which takes precedence over the bus's default. A
+// non-positive timeout defers to the default.
+type TimedSubscriber interface {
+	Timeout() time.Duration
+}
+
+// invocationKey is the context key under which invoke stores the invocation a handler's context belongs to.
+type invocationKey struct{}
+
+// An invocation records the context of the Emittable a handler was invoked with, along with the deadline of the context
+// handed to the handler, which includes the handler timeout.
+type invocation struct {
+	parent   context.Context
+	deadline time.Time
+	timed    bool
+}
+
+// Inherit returns the context that an Emittable posted with ctx carries. Contexts handed to a ContextSubscriber are
+// canceled once their handler returns, so an Emittable posted with one of them, or with a context derived from it,
+// carries its values and the deadlines added by the handler, but is only canceled along with the context of the
+// Emittable being handled. Any other context is returned as is.
+func Inherit(ctx context.Context) context.Context {
+	inv, ok := ctx.Value(invocationKey{}).(*invocation)
+	if !ok {
+		return ctx
+	}
+
+	// The invocation is shadowed so that inheriting the returned context again leaves it as is.
+	detached := context.WithValue(context.WithoutCancel(ctx), invocationKey{}, nil)
+
+	var (
+		inherited context.Context
+		cancel    context.CancelFunc
+	)
+
+	if deadline, ok := inv.inheritedDeadline(ctx); ok {
+		inherited, cancel = context.WithDeadline(detached, deadline)
+	} else {
+		inherited, cancel = context.WithCancel(detached)
+	}
+
+	context.AfterFunc(inv.parent, cancel)
+	return inherited
+}
+
+// inheritedDeadline returns the earliest of the deadline of the parent context and any deadline added by the handler to
+// ctx, leaving out the handler timeout.
+func (inv *invocation) inheritedDeadline(ctx context.Context) (time.Time, bool) {
+	deadline, ok := inv.parent.Deadline()
+
+	d, set := ctx.Deadline()
+	if set && (!inv.timed || d.Before(inv.deadline)) && (!ok || d.Before(deadline)) {
+		return d, true
+	}
+
+	return deadline, ok
+}
+
+// invoke calls the Subscriber's handler with a context derived from parent that is also canceled along with the bus's
+// context. If a handler timeout applies and the handler does not return in time, invoke stops waiting for it and
+// returns an error wrapping ErrHandlerTimeout; the handler keeps running in the background, but no longer occupies a
+// worker or blocks the tick.
+func (b *Bus[EM, SU]) invoke(parent context.Context, em EM, s SU) error {
+	inv := &invocation{parent: parent}
+
+	ctx := context.WithValue(parent, invocationKey{}, inv)
+	if b.options.Context.Done() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+
+		stop := context.AfterFunc(b.options.Context, cancel)
+		defer stop()
+	}
+
+	timeout := b.timeout(s)
+	if timeout <= 0 {
+		inv.deadline, inv.timed = ctx.Deadline()
+		return b.handle(ctx, em, s)
+	}
+
+	ctx, cancelTimeout := context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w after %v", ErrHandlerTimeout, timeout))
+	defer cancelTimeout()
+
+	inv.deadline, inv.timed = ctx.Deadline()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.handle(ctx, em, s)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// timeout returns the handler timeout that applies to s.
+func (b *Bus[EM, SU]) timeout(s SU) time.Duration {
+	if ts, ok := any(s).(TimedSubscriber); ok && ts.Timeout() > 0 {
+		return ts.Timeout()
+	}
+
+	return b.options.HandlerTimeout
+}
@@ -0,0 +1,30 @@
+package bus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// A Failure describes an error returned by a Subscriber while handling an Emittable. It is passed to the error builder
+// so that the resulting Emittable can carry the context of the error.
+type Failure struct {
+	// Err is the error returned by the Subscriber.
+	Err error
+
+	// Emittable is the Emittable the Subscriber failed to handle.
+	Emittable Emittable
+
+	// Subscriber is the Subscriber that failed, and SubscriberID is its ID.
+	Subscriber   any
+	SubscriberID uuid.UUID
+
+	// Topic is the topic of the Emittable.
+	Topic string
+
+	// Duration is how long the Subscriber's handler ran before failing.
+	Duration time.Duration
+
+	// Attempt is the number of times the Subscriber has attempted to handle the Emittable, starting from one.
+	Attempt int
+}
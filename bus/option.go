@@ -1,21 +1,52 @@
 package bus
 
 import (
+	"context"
 	"runtime"
+	"slices"
+	"time"
 )
 
 type Option func(*Options)
 
 type Options struct {
-	Demuxers     int
-	ErrorBuilder func(error) Emittable
+	Demuxers      int
+	ErrorBuilder  func(failure Failure) Emittable
+	DropHandler   func(em Emittable, reason error)
+	RejectHandler func(em Emittable, reason error)
+	PostHook      func(em Emittable)
+	Sequential    []string
+	Repanic       bool
+
+	TopicCacheSize int
+
+	RetryPolicies []TopicRetryPolicy
+	TickDurations map[string]time.Duration
+
+	CircuitBreaker *CircuitBreaker
+	CircuitBuilder func(change CircuitChange) Emittable
+
+	Context        context.Context
+	HandlerTimeout time.Duration
+
+	TickBudget  TickBudget
+	QueueGroups []QueueGroup
+	Phases      []Phase
 }
 
 func NewOptions(opts ...Option) *Options {
 	// Default settings.
 	options := &Options{
-		Demuxers: runtime.NumCPU(),
-		ErrorBuilder: func(err error) Emittable {
+		Demuxers:       runtime.NumCPU(),
+		TopicCacheSize: DefaultTopicCacheSize,
+		ErrorBuilder: func(failure Failure) Emittable {
+			return nil
+		},
+		DropHandler:   func(em Emittable, reason error) {},
+		RejectHandler: func(em Emittable, reason error) {},
+		PostHook:      func(em Emittable) {},
+		Context:       context.Background(),
+		CircuitBuilder: func(change CircuitChange) Emittable {
 			return nil
 		},
 	}
@@ -37,8 +68,172 @@ func WithDemuxers(n int) Option {
 	}
 }
 
-func WithErrorBuilder(builder func(error) Emittable) Option {
+// WithTopicCacheSize sets the number of concrete topics whose routing, queue group and phase the bus caches. Once the
+// cache is full, an arbitrary topic is evicted to make room for another. A non-positive size disables caching.
+func WithTopicCacheSize(size int) Option {
+	return func(options *Options) {
+		options.TopicCacheSize = size
+	}
+}
+
+// WithErrorBuilder sets the function used to build the Emittable posted whenever a Subscriber fails. If the builder
+// returns nil, or an Emittable of a type the bus cannot route, nothing is posted.
+func WithErrorBuilder(builder func(failure Failure) Emittable) Option {
 	return func(options *Options) {
 		options.ErrorBuilder = builder
 	}
 }
+
+// WithDropHandler sets the function called whenever the bus drops an Emittable instead of routing it. The reason is an
+// error such as ErrNoSubscribers describing why it was dropped.
+func WithDropHandler(handler func(em Emittable, reason error)) Option {
+	return func(options *Options) {
+		options.DropHandler = handler
+	}
+}
+
+// WithRejectHandler sets the function called whenever an Emittable is routed to subscriptions, but none of them admit
+// it. The reason is ErrCircuitOpen if any of them would have admitted it but for its open circuit, and ErrFiltered
+// otherwise.
+func WithRejectHandler(handler func(em Emittable, reason error)) Option {
+	return func(options *Options) {
+		options.RejectHandler = handler
+	}
+}
+
+// WithPostHook sets the function called whenever an Emittable is posted, including those posted by the bus itself,
+// such as those built by the error builder. It is called once the Emittable has been queued.
+func WithPostHook(hook func(em Emittable)) Option {
+	return func(options *Options) {
+		options.PostHook = hook
+	}
+}
+
+// WithSequential enables sequential dispatch for every topic matched by one of patterns. Instead of being handled
+// concurrently, an Emittable posted to such a topic is handled by one Subscriber at a time in order of priority (see
+// PrioritizedSubscriber), and canceling it prevents the remaining Subscribers from handling it.
+func WithSequential(patterns ...string) Option {
+	return func(options *Options) {
+		options.Sequential = append(options.Sequential, patterns...)
+	}
+}
+
+// WithRepanic determines whether a panic raised by a Subscriber crashes the program. By default, the bus recovers the
+// panic and reports it through the error path as a PanicError; enabling repanicking is mainly useful in development.
+func WithRepanic(enabled bool) Option {
+	return func(options *Options) {
+		options.Repanic = enabled
+	}
+}
+
+// WithRetryPolicy sets the RetryPolicy of every topic matched by pattern. When several patterns match a topic, the
+// policy that was set first applies. Subscribers implementing RetryingSubscriber may override it.
+func WithRetryPolicy(pattern string, policy RetryPolicy) Option {
+	return func(options *Options) {
+		options.RetryPolicies = append(options.RetryPolicies, TopicRetryPolicy{
+			Pattern: pattern,
+			Policy:  policy,
+		})
+	}
+}
+
+// WithTickDuration sets the wall time that a tick of a queue group stands for in retry delays. It is meant for groups
+// that are not ticked at a fixed rate, whose ticks cannot be relied on to arrive: the ticks of the Delay before every
+// retry of the group are converted to wall time, and the retry is due on the first tick of the group once that time
+// has passed.
+func WithTickDuration(group string, d time.Duration) Option {
+	return func(options *Options) {
+		if options.TickDurations == nil {
+			options.TickDurations = make(map[string]time.Duration)
+		}
+
+		options.TickDurations[group] = d
+	}
+}
+
+// WithCircuitBreaker enables circuit breaking. The bus tracks the failure rate of every Subscriber and stops handing
+// Emittable types to a Subscriber whose failure rate reaches the threshold until its cooldown is over.
+func WithCircuitBreaker(cb CircuitBreaker) Option {
+	if cb.Threshold <= 0 || cb.Threshold > 1 {
+		cb.Threshold = DefaultCircuitThreshold
+	}
+
+	return func(options *Options) {
+		options.CircuitBreaker = &cb
+	}
+}
+
+// WithCircuitBuilder sets the function used to build the Emittable posted whenever a circuit changes state. If the
+// builder returns nil, or an Emittable of a type the bus cannot route, nothing is posted.
+func WithCircuitBuilder(builder func(change CircuitChange) Emittable) Option {
+	return func(options *Options) {
+		options.CircuitBuilder = builder
+	}
+}
+
+// WithContext sets the context handed to every ContextSubscriber. Canceling it cancels every handler that is running
+// or will run.
+func WithContext(ctx context.Context) Option {
+	return func(options *Options) {
+		options.Context = ctx
+	}
+}
+
+// WithHandlerTimeout sets the default handler timeout. A handler that does not return before its timeout is reported
+// through the error path with an error wrapping ErrHandlerTimeout, and its context is canceled. A non-positive timeout
+// disables the default.
+func WithHandlerTimeout(d time.Duration) Option {
+	return func(options *Options) {
+		options.HandlerTimeout = d
+	}
+}
+
+// WithTickBudget limits how much work a single tick may do (see TickBudget).
+func WithTickBudget(budget TickBudget) Option {
+	return func(options *Options) {
+		options.TickBudget = budget
+	}
+}
+
+// WithQueueGroup assigns every topic matched by one of patterns to the named queue group (see QueueGroup). A topic
+// matched by several queue groups is assigned to the one declared first. Naming the DefaultGroup has no effect.
+func WithQueueGroup(name string, patterns ...string) Option {
+	return func(options *Options) {
+		if name == DefaultGroup {
+			return
+		}
+
+		i := slices.IndexFunc(options.QueueGroups, func(group QueueGroup) bool { return group.Name == name })
+		if i < 0 {
+			options.QueueGroups = append(options.QueueGroups, QueueGroup{
+				Name: name,
+			})
+
+			i = len(options.QueueGroups) - 1
+		}
+
+		options.QueueGroups[i].Patterns = append(options.QueueGroups[i].Patterns, patterns...)
+	}
+}
+
+// WithPhase assigns every topic matched by one of patterns to the named phase (see Phase). Phases run in the order
+// they are first declared, after the default phase. A topic matched by several phases is assigned to the one declared
+// first. Naming the DefaultPhase has no effect.
+func WithPhase(name string, patterns ...string) Option {
+	return func(options *Options) {
+		if name == DefaultPhase {
+			return
+		}
+
+		i := slices.IndexFunc(options.Phases, func(phase Phase) bool { return phase.Name == name })
+		if i < 0 {
+			options.Phases = append(options.Phases, Phase{
+				Name: name,
+			})
+
+			i = len(options.Phases) - 1
+		}
+
+		options.Phases[i].Patterns = append(options.Phases[i].Patterns, patterns...)
+	}
+}
@@ -0,0 +1,49 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// A PanicError is reported through the error path when a Subscriber panics while handling an Emittable.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("subscriber panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// handle calls the Subscriber's handler, converting a panic into a PanicError unless the bus is configured to repanic.
+func (b *Bus[EM, SU]) handle(ctx context.Context, em EM, s SU) (err error) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+
+		// Panicking from within the deferred call preserves the original stack in the crash report.
+		if b.options.Repanic {
+			panic(v)
+		}
+
+		err = &PanicError{
+			Value: v,
+			Stack: debug.Stack(),
+		}
+	}()
+
+	if cs, ok := any(s).(ContextSubscriber[EM]); ok {
+		return cs.HandleContext(ctx, em)
+	}
+
+	return s.Handle(em)
+}
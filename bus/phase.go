@@ -0,0 +1,41 @@
+package bus
+
+import (
+	"slices"
+)
+
+// DefaultPhase is the name of the phase of every topic that is not assigned to another phase. It is always the first
+// phase of a tick.
+const DefaultPhase = ""
+
+// A Phase is a stage of every tick. The phases of a tick run one after the other in the order they were declared,
+// after the default phase, and every Emittable dispatched during a phase is handled before the next phase begins. An
+// Emittable posted during a phase to a topic of a later phase is dispatched within the same tick, while one posted to
+// the same or an earlier phase waits for the next tick.
+type Phase struct {
+	Name     string
+	Patterns []string
+}
+
+// phaseOf returns the index of the phase topic is assigned to, which is the first Phase with a pattern that matches
+// it, or the default phase if none does.
+func (b *Bus[EM, SU]) phaseOf(topic string) int {
+	if len(b.options.Phases) == 0 {
+		return 0
+	}
+
+	if i, ok := b.phases.Load(topic); ok {
+		return i
+	}
+
+	phase := 0
+	for i, p := range b.options.Phases {
+		if slices.ContainsFunc(p.Patterns, func(pattern string) bool { return Match(pattern, topic) }) {
+			phase = i + 1
+			break
+		}
+	}
+
+	b.phases.Store(topic, phase)
+	return phase
+}
@@ -0,0 +1,198 @@
+package bus
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/AndrewChon/pqueue"
+)
+
+// DefaultGroup is the name of the queue group of every topic that is not assigned to another queue group.
+const DefaultGroup = ""
+
+// A QueueGroup assigns the topics matched by its patterns to a queue of their own, which is only dispatched when the
+// group is ticked (see Bus.TickGroup). Queue groups share the workers and subscribers of their bus, so each can be
+// ticked at its own cadence without affecting how Emittable types are routed.
+type QueueGroup struct {
+	Name     string
+	Patterns []string
+}
+
+// A queue holds the Emittable types of a queue group until they are dispatched.
+type queue[EM Emittable] struct {
+	name string
+
+	// stages holds the Emittable types of every phase of a tick, starting with the default phase (see Phase).
+	stages []*stage[EM]
+
+	// pending counts the Emittable types waiting to be dispatched, per topic. It is guarded by bufferMu.
+	pending map[string]int
+
+	// ticks counts the ticks of the group that have begun, slowest records the slowest handler of the tick in progress
+	// and wg tracks the tasks dispatched by the phase in progress.
+	ticks   atomic.Uint64
+	slowest HandlerTiming
+	wg      sync.WaitGroup
+
+	// bufferMu guards the buffer of every stage, and lanesMu the lanes of every stage.
+	bufferMu  sync.Mutex
+	lanesMu   sync.Mutex
+	slowestMu sync.Mutex
+	tickMu    sync.Mutex
+}
+
+// A stage holds the Emittable types of a single phase of a queue group.
+type stage[EM Emittable] struct {
+	buffer  PriorityQueue[uint8, *envelope[EM]]
+	working PriorityQueue[uint8, *envelope[EM]]
+
+	// lanes holds the Emittable types of every partition in the order they were posted. The priority queues only
+	// decide when a partition is served; the Emittable that is handled is always the oldest one in its lane.
+	lanes map[string]*pqueue.CircularBuffer[*envelope[EM]]
+}
+
+func newQueue[EM Emittable](name string, phases int) *queue[EM] {
+	q := &queue[EM]{
+		name:    name,
+		pending: make(map[string]int),
+	}
+
+	for range phases {
+		q.stages = append(q.stages, &stage[EM]{
+			buffer:  pqueue.NewPairing[uint8, *envelope[EM]](),
+			working: pqueue.NewPairing[uint8, *envelope[EM]](),
+			lanes:   make(map[string]*pqueue.CircularBuffer[*envelope[EM]]),
+		})
+	}
+
+	return q
+}
+
+// push queues an envelope with the given priority in the stage of its phase.
+func (q *queue[EM]) push(env *envelope[EM], priority uint8) {
+	st := q.stages[env.phase]
+
+	if key := partitionKey(env.em); key != "" {
+		q.lanesMu.Lock()
+		lane, ok := st.lanes[key]
+		if !ok {
+			lane = pqueue.NewCircularBuffer[*envelope[EM]]()
+			st.lanes[key] = lane
+		}
+
+		lane.Push(env)
+		q.lanesMu.Unlock()
+	}
+
+	q.bufferMu.Lock()
+	st.buffer.Push(env, priority)
+	q.pending[env.em.Topic()]++
+	q.bufferMu.Unlock()
+}
+
+// meld moves every buffered envelope of a stage into its working queue.
+func (q *queue[EM]) meld(st *stage[EM]) {
+	q.bufferMu.Lock()
+	defer q.bufferMu.Unlock()
+
+	working := st.working.(*pqueue.Pairing[uint8, *envelope[EM]])
+	working.Meld(st.buffer.(*pqueue.Pairing[uint8, *envelope[EM]]))
+}
+
+// dispatched records that the given number of Emittable types have been dispatched, per topic.
+func (q *queue[EM]) dispatched(counts map[string]int) {
+	q.bufferMu.Lock()
+	defer q.bufferMu.Unlock()
+
+	for topic, n := range counts {
+		if q.pending[topic] -= n; q.pending[topic] <= 0 {
+			delete(q.pending, topic)
+		}
+	}
+}
+
+// nextInLane removes and returns the oldest Emittable in the lane of key of a stage. If the lane is empty, which can
+// only happen if the key of an Emittable changed after it was posted, fallback is returned instead.
+func (q *queue[EM]) nextInLane(st *stage[EM], key string, fallback *envelope[EM]) *envelope[EM] {
+	q.lanesMu.Lock()
+	defer q.lanesMu.Unlock()
+
+	lane, ok := st.lanes[key]
+	if !ok {
+		return fallback
+	}
+
+	env, _ := lane.Pop()
+
+	if lane.Size() == 0 {
+		delete(st.lanes, key)
+	}
+
+	return env
+}
+
+// discard removes every queued envelope and returns them.
+func (q *queue[EM]) discard() []*envelope[EM] {
+	q.bufferMu.Lock()
+	clear(q.pending)
+	q.bufferMu.Unlock()
+
+	var discarded []*envelope[EM]
+	for _, st := range q.stages {
+		q.meld(st)
+
+		// Envelopes that belong to a partition are taken from their lanes, since the queue may not hold them in the
+		// order they were posted.
+		for env, ok := st.working.Pop(); ok; env, ok = st.working.Pop() {
+			if partitionKey(env.em) == "" {
+				discarded = append(discarded, env)
+			}
+		}
+
+		q.lanesMu.Lock()
+		for key, lane := range st.lanes {
+			for env, ok := lane.Pop(); ok; env, ok = lane.Pop() {
+				discarded = append(discarded, env)
+			}
+
+			delete(st.lanes, key)
+		}
+		q.lanesMu.Unlock()
+	}
+
+	return discarded
+}
+
+// size returns the number of queued envelopes.
+func (q *queue[EM]) size() int {
+	size := 0
+	for _, st := range q.stages {
+		size += st.buffer.Size() + st.working.Size()
+	}
+
+	return size
+}
+
+// queueOf returns the queue of the group topic is assigned to, which is the first QueueGroup with a pattern that
+// matches it, or the default group if none does.
+func (b *Bus[EM, SU]) queueOf(topic string) *queue[EM] {
+	if len(b.options.QueueGroups) == 0 {
+		return b.queues[DefaultGroup]
+	}
+
+	if q, ok := b.groups.Load(topic); ok {
+		return q
+	}
+
+	q := b.queues[DefaultGroup]
+	for _, group := range b.options.QueueGroups {
+		if slices.ContainsFunc(group.Patterns, func(pattern string) bool { return Match(pattern, topic) }) {
+			q = b.queues[group.Name]
+			break
+		}
+	}
+
+	b.groups.Store(topic, q)
+	return q
+}
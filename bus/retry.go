@@ -0,0 +1,286 @@
+package bus
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// A Delay is an amount of time measured in ticks, wall time, or both. A Delay is over once both have elapsed.
+type Delay struct {
+	Ticks    uint64
+	Duration time.Duration
+}
+
+// A Backoff computes the Delay to wait before a retry. The attempt is the one about to be made, starting from two.
+type Backoff interface {
+	Delay(attempt int) Delay
+}
+
+// BackoffFunc is an adapter that allows an ordinary function to be used as a Backoff.
+type BackoffFunc func(attempt int) Delay
+
+func (f BackoffFunc) Delay(attempt int) Delay {
+	return f(attempt)
+}
+
+// FixedBackoff returns a Backoff that always waits d.
+func FixedBackoff(d time.Duration) Backoff {
+	return BackoffFunc(func(_ int) Delay {
+		return Delay{Duration: d}
+	})
+}
+
+// FixedTickBackoff returns a Backoff that always waits the given number of ticks.
+func FixedTickBackoff(ticks uint64) Backoff {
+	return BackoffFunc(func(_ int) Delay {
+		return Delay{Ticks: ticks}
+	})
+}
+
+// ExponentialBackoff returns a Backoff that waits base before the first retry and doubles the wait for every
+// subsequent retry, up to maximum. Each wait is then reduced by a random proportion of up to jitter, which should be
+// between zero and one.
+func ExponentialBackoff(base, maximum time.Duration, jitter float64) Backoff {
+	return BackoffFunc(func(attempt int) Delay {
+		return Delay{Duration: time.Duration(exponential(float64(base), float64(maximum), jitter, attempt))}
+	})
+}
+
+// ExponentialTickBackoff returns a Backoff that waits base ticks before the first retry and doubles the wait for every
+// subsequent retry, up to maximum. Each wait is then reduced by a random proportion of up to jitter, which should be
+// between zero and one.
+func ExponentialTickBackoff(base, maximum uint64, jitter float64) Backoff {
+	return BackoffFunc(func(attempt int) Delay {
+		return Delay{Ticks: uint64(exponential(float64(base), float64(maximum), jitter, attempt))}
+	})
+}
+
+func exponential(base, maximum, jitter float64, attempt int) float64 {
+	wait := base
+	for i := 2; i < attempt && wait < maximum; i++ {
+		wait *= 2
+	}
+
+	wait = min(wait, maximum)
+	return wait - wait*jitter*rand.Float64()
+}
+
+// A RetryPolicy determines whether and when a Subscriber should handle an Emittable again after failing to handle it.
+// Retries wait for the circuit of the Subscriber to let them through (see CircuitBreaker). A retry of an Emittable
+// that was canceled, or of a Subscriber that unsubscribed, is given up on and reported with the error of the last
+// attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the Emittable is handled, including the first attempt. Values less
+	// than two disable retries.
+	MaxAttempts int
+
+	// Backoff computes the delay before each retry. If nil, retries happen on the next tick.
+	Backoff Backoff
+
+	// RetryableOnly restricts retries to errors marked with Retryable. Otherwise, every error is retried unless it
+	// is marked with Permanent.
+	RetryableOnly bool
+}
+
+// A RetryingSubscriber is a Subscriber with its own RetryPolicy, which takes precedence over any policy configured for
+// the topics it is subscribed to. Returning nil defers to those policies.
+type RetryingSubscriber interface {
+	RetryPolicy() *RetryPolicy
+}
+
+// A PermanentError is an error that must not be retried.
+type PermanentError struct {
+	Err error
+}
+
+// Permanent marks err as an error that must not be retried.
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// A RetryableError is an error that may be retried, even under a RetryPolicy with RetryableOnly set.
+type RetryableError struct {
+	Err error
+}
+
+// Retryable marks err as an error that may be retried.
+func Retryable(err error) error {
+	return &RetryableError{Err: err}
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// allows reports whether an Emittable should be handled again after attempt failed with err.
+func (p *RetryPolicy) allows(err error, attempt int) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	var retryable *RetryableError
+	return !p.RetryableOnly || errors.As(err, &retryable)
+}
+
+// delay returns the Delay to wait before attempt.
+func (p *RetryPolicy) delay(attempt int) Delay {
+	if p.Backoff == nil {
+		return Delay{}
+	}
+
+	return p.Backoff.Delay(attempt)
+}
+
+// A TopicRetryPolicy is a RetryPolicy that applies to every topic matched by its pattern.
+type TopicRetryPolicy struct {
+	Pattern string
+	Policy  RetryPolicy
+}
+
+// A retry is an Emittable waiting to be handled again by the Subscriber that failed to handle it, along with the
+// failure of the last attempt.
+type retry[EM Emittable, SU Subscriber[EM]] struct {
+	env     *envelope[EM]
+	sub     *subscription[EM, SU]
+	failure Failure
+	dueTick uint64
+	dueTime time.Time
+}
+
+// retryPolicy returns the RetryPolicy that applies when s fails to handle em, or nil if there is none.
+func (b *Bus[EM, SU]) retryPolicy(em EM, s SU) *RetryPolicy {
+	if rs, ok := any(s).(RetryingSubscriber); ok {
+		if policy := rs.RetryPolicy(); policy != nil {
+			return policy
+		}
+	}
+
+	for i := range b.options.RetryPolicies {
+		if Match(b.options.RetryPolicies[i].Pattern, em.Topic()) {
+			return &b.options.RetryPolicies[i].Policy
+		}
+	}
+
+	return nil
+}
+
+// scheduleRetry schedules an Emittable to be handled by sub again once the policy's delay before the next attempt is
+// over.
+func (b *Bus[EM, SU]) scheduleRetry(
+	env *envelope[EM],
+	sub *subscription[EM, SU],
+	policy *RetryPolicy,
+	failure Failure,
+) {
+	delay := policy.delay(failure.Attempt + 1)
+
+	// Ticks are converted to wall time for queue groups that are given a tick duration (see WithTickDuration).
+	if d, ok := b.options.TickDurations[env.q.name]; ok {
+		delay = Delay{Ticks: 1, Duration: delay.Duration + time.Duration(delay.Ticks)*d}
+	}
+
+	b.retriesMu.Lock()
+	defer b.retriesMu.Unlock()
+
+	// A retry can happen on the next tick of the queue group at the earliest.
+	b.retries = append(b.retries, &retry[EM, SU]{
+		env:     env,
+		sub:     sub,
+		failure: failure,
+		dueTick: env.q.ticks.Load() + max(delay.Ticks, 1),
+		dueTime: time.Now().Add(delay.Duration),
+	})
+}
+
+// holdRetry puts back a due retry that the circuit of its subscription did not let through, so that it is made on a
+// later tick of the queue group once the circuit allows it.
+func (b *Bus[EM, SU]) holdRetry(r *retry[EM, SU]) {
+	r.dueTick = r.env.q.ticks.Load() + 1
+	r.dueTime = r.sub.circuit.allowedAt(b.options.CircuitBreaker)
+
+	b.retriesMu.Lock()
+	defer b.retriesMu.Unlock()
+
+	b.retries = append(b.retries, r)
+}
+
+// dueRetries removes and returns every retry of the queue group of q that is due on its current tick.
+func (b *Bus[EM, SU]) dueRetries(q *queue[EM]) []*retry[EM, SU] {
+	b.retriesMu.Lock()
+	defer b.retriesMu.Unlock()
+
+	tick := q.ticks.Load()
+	now := time.Now()
+
+	var due []*retry[EM, SU]
+	pending := b.retries[:0]
+	for _, r := range b.retries {
+		if r.env.q == q && r.dueTick <= tick && !now.Before(r.dueTime) {
+			due = append(due, r)
+			continue
+		}
+
+		pending = append(pending, r)
+	}
+
+	clear(b.retries[len(pending):])
+	b.retries = pending
+
+	return due
+}
+
+// Retrying returns the number of Emittable types waiting to be handled again by a Subscriber that failed to handle
+// them.
+func (b *Bus[EM, SU]) Retrying() int {
+	b.retriesMu.Lock()
+	defer b.retriesMu.Unlock()
+
+	return len(b.retries)
+}
+
+// NextRetry returns the earliest time at which an Emittable of a queue group is due to be retried on the next tick of
+// the group, and whether any is. Retries without a backoff are reported as already due, while retries that are still
+// more than one tick away are not reported, since no amount of waiting makes them due (see WithTickDuration).
+func (b *Bus[EM, SU]) NextRetry(group string) (time.Time, bool) {
+	b.retriesMu.Lock()
+	defer b.retriesMu.Unlock()
+
+	q, ok := b.queues[group]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	next := q.ticks.Load() + 1
+
+	var due time.Time
+	found := false
+	for _, r := range b.retries {
+		if r.env.q != q || r.dueTick > next {
+			continue
+		}
+
+		if !found || r.dueTime.Before(due) {
+			due, found = r.dueTime, true
+		}
+	}
+
+	return due, found
+}
@@ -0,0 +1,117 @@
+package bus
+
+// A Filter is a predicate that decides whether an Emittable should be handled by a Subscriber. Filters are evaluated
+// before a handling task is scheduled, so an Emittable that is filtered out never occupies a worker.
+type Filter[EM Emittable] func(em EM) bool
+
+// A FilteredSubscriber is a Subscriber that is only interested in a subset of the Emittable types routed to it.
+type FilteredSubscriber[EM Emittable] interface {
+	Filter(em EM) bool
+}
+
+// A PrioritizedSubscriber is a Subscriber that declares the order in which it handles an Emittable relative to the
+// other Subscribers matched by the same topic. Priority is called once for every pattern the Subscriber is registered
+// under; lower priorities are handled first, and Subscribers that do not implement PrioritizedSubscriber have a
+// priority of zero.
+type PrioritizedSubscriber interface {
+	Priority(pattern string) int
+}
+
+// All returns a Filter that accepts an Emittable only if every one of filters accepts it.
+func All[EM Emittable](filters ...Filter[EM]) Filter[EM] {
+	return func(em EM) bool {
+		for _, filter := range filters {
+			if !filter(em) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Any returns a Filter that accepts an Emittable if at least one of filters accepts it.
+func Any[EM Emittable](filters ...Filter[EM]) Filter[EM] {
+	return func(em EM) bool {
+		for _, filter := range filters {
+			if filter(em) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not returns a Filter that accepts an Emittable only if filter rejects it.
+func Not[EM Emittable](filter Filter[EM]) Filter[EM] {
+	return func(em EM) bool {
+		return !filter(em)
+	}
+}
+
+// A subscription is the registration of a Subscriber with the bus, along with the state the bus keeps for it.
+type subscription[EM Emittable, SU Subscriber[EM]] struct {
+	s        SU
+	patterns []string
+	filter   Filter[EM]
+	circuit  *circuit
+}
+
+func newSubscription[EM Emittable, SU Subscriber[EM]](s SU, filters []Filter[EM]) *subscription[EM, SU] {
+	if fs, ok := any(s).(FilteredSubscriber[EM]); ok {
+		filters = append([]Filter[EM]{fs.Filter}, filters...)
+	}
+
+	sub := &subscription[EM, SU]{
+		s:        s,
+		patterns: patternsOf(s),
+	}
+
+	switch len(filters) {
+	case 0:
+	case 1:
+		sub.filter = filters[0]
+	default:
+		sub.filter = All(filters...)
+	}
+
+	return sub
+}
+
+// bindings returns a binding for every pattern the subscription is registered under.
+func (sub *subscription[EM, SU]) bindings() []binding[EM, SU] {
+	ps, prioritized := any(sub.s).(PrioritizedSubscriber)
+
+	bindings := make([]binding[EM, SU], len(sub.patterns))
+	for i, pattern := range sub.patterns {
+		bindings[i] = binding[EM, SU]{
+			pattern: pattern,
+			sub:     sub,
+		}
+
+		if prioritized {
+			bindings[i].priority = ps.Priority(pattern)
+		}
+	}
+
+	return bindings
+}
+
+// accepts reports whether the subscription's filter, if any, accepts em.
+func (sub *subscription[EM, SU]) accepts(em EM) bool {
+	return sub.filter == nil || sub.filter(em)
+}
+
+// A binding ties a subscription to one of its patterns.
+type binding[EM Emittable, SU Subscriber[EM]] struct {
+	pattern  string
+	priority int
+	sub      *subscription[EM, SU]
+}
+
+// A route is the set of bindings matched by a concrete topic, ordered by priority.
+type route[EM Emittable, SU Subscriber[EM]] struct {
+	bindings   []binding[EM, SU]
+	sequential bool
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/AndrewChon/banji/bus"
+
+	"github.com/google/uuid"
+)
+
+type recordingSubscriber struct {
+	id      uuid.UUID
+	mu      sync.Mutex
+	handled map[uuid.UUID]bool
+}
+
+func (s *recordingSubscriber) ID() uuid.UUID {
+	return s.id
+}
+
+func (s *recordingSubscriber) Topic() string {
+	return MockTopic
+}
+
+func (s *recordingSubscriber) Handle(em *MockEmittable) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handled[em.ID()] = true
+	return nil
+}
+
+func TestTickBudget(t *testing.T) {
+	const (
+		budget = 4
+		urgent = 4
+		normal = 6
+	)
+
+	bs := bus.NewBus[*MockEmittable, *recordingSubscriber](
+		bus.WithDemuxers(Demuxers),
+		bus.WithTickBudget(bus.TickBudget{
+			MaxEvents: budget,
+		}),
+	)
+
+	s := &recordingSubscriber{
+		id:      uuid.New(),
+		handled: make(map[uuid.UUID]bool),
+	}
+
+	bs.Subscribe(s)
+
+	for range normal {
+		bs.Post(NewMockEmittable(MockTopic), 1)
+	}
+
+	// Urgent Emittable types are posted last, but must still be dispatched first.
+	var urgents []*MockEmittable
+	for range urgent {
+		em := NewMockEmittable(MockTopic)
+		urgents = append(urgents, em)
+		bs.Post(em, 0)
+	}
+
+	stats := bs.Tick()
+	if stats.Dispatched != budget || stats.CarriedOver != urgent+normal-budget {
+		t.Fatalf("Expected %d dispatched and %d carried over, received %+v\n", budget, urgent+normal-budget, stats)
+	}
+
+	for _, em := range urgents {
+		if !s.handled[em.ID()] {
+			t.Fatalf("Expected every urgent emittable to be handled on the first tick\n")
+		}
+	}
+
+	if pending := bs.Pending()[MockTopic]; pending != stats.CarriedOver {
+		t.Fatalf("Expected %d pending emittables, received %d\n", stats.CarriedOver, pending)
+	}
+
+	// Carried-over Emittable types are dispatched on the following ticks, within the same budget.
+	stats = bs.Tick()
+	if stats.Dispatched != budget || stats.CarriedOver != normal-budget {
+		t.Fatalf("Expected %d dispatched and %d carried over, received %+v\n", budget, normal-budget, stats)
+	}
+
+	stats = bs.Tick()
+	if stats.Dispatched != normal-budget || stats.CarriedOver != 0 || bs.Size() != 0 {
+		t.Fatalf("Expected the queue to be drained, received %+v\n", stats)
+	}
+
+	if len(s.handled) != urgent+normal || len(bs.Pending()) != 0 {
+		t.Fatalf("Expected %d emittables to be handled, received %d\n", urgent+normal, len(s.handled))
+	}
+}
@@ -0,0 +1,147 @@
+package test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji/bus"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CircuitWindow   = 4
+	CircuitCooldown = 50 * time.Millisecond
+)
+
+type circuitRecorder struct {
+	mu     sync.Mutex
+	states []bus.CircuitState
+}
+
+func (r *circuitRecorder) build(change bus.CircuitChange) bus.Emittable {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.states = append(r.states, change.State)
+	return nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	recorder := new(circuitRecorder)
+	bs := bus.NewBus[*MockEmittable, *flakySubscriber](
+		bus.WithDemuxers(1),
+		bus.WithCircuitBreaker(bus.CircuitBreaker{
+			Window:    CircuitWindow,
+			Threshold: 0.5,
+			Cooldown:  CircuitCooldown,
+		}),
+		bus.WithCircuitBuilder(recorder.build),
+	)
+
+	// The subscriber fails until the circuit has opened, then recovers.
+	s := &flakySubscriber{id: uuid.New(), failures: CircuitWindow, wrap: func(err error) error { return err }}
+	bs.Subscribe(s)
+
+	postAndTick := func(n int) {
+		for range n {
+			bs.Post(NewMockEmittable(MockTopic), 0)
+			bs.Tick()
+		}
+	}
+
+	postAndTick(2 * CircuitWindow)
+
+	if s.attempts != CircuitWindow {
+		t.Fatalf("Expected %d attempts before the circuit opened, received %d\n", CircuitWindow, s.attempts)
+	}
+
+	if state := bs.CircuitState(s.ID()); state != bus.CircuitOpen {
+		t.Fatalf("Expected an open circuit, found %v\n", state)
+	}
+
+	time.Sleep(CircuitCooldown)
+	postAndTick(1)
+
+	if state := bs.CircuitState(s.ID()); state != bus.CircuitClosed {
+		t.Fatalf("Expected a closed circuit, found %v\n", state)
+	}
+
+	expected := []bus.CircuitState{bus.CircuitOpen, bus.CircuitHalfOpen, bus.CircuitClosed}
+	if !slices.Equal(recorder.states, expected) {
+		t.Fatalf("Expected circuit transitions %v, received %v\n", expected, recorder.states)
+	}
+}
+
+func TestCircuitBreakerDefaultThreshold(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, *flakySubscriber](
+		bus.WithDemuxers(1),
+		bus.WithCircuitBreaker(bus.CircuitBreaker{
+			Window:   CircuitWindow,
+			Cooldown: time.Minute,
+		}),
+	)
+
+	// A subscriber that never fails must never be quarantined, even without a threshold.
+	s := &flakySubscriber{id: uuid.New(), wrap: func(err error) error { return err }}
+	bs.Subscribe(s)
+
+	for range 2 * CircuitWindow {
+		bs.Post(NewMockEmittable(MockTopic), 0)
+		bs.Tick()
+	}
+
+	if state := bs.CircuitState(s.ID()); state != bus.CircuitClosed {
+		t.Fatalf("Expected a closed circuit, found %v\n", state)
+	}
+
+	if s.attempts != 2*CircuitWindow {
+		t.Fatalf("Expected %d attempts, received %d\n", 2*CircuitWindow, s.attempts)
+	}
+}
+
+func TestCircuitBreakerRetry(t *testing.T) {
+	failures := new(failureRecorder)
+	bs := bus.NewBus[*MockEmittable, *flakySubscriber](
+		bus.WithDemuxers(1),
+		bus.WithErrorBuilder(failures.build),
+		bus.WithRetryPolicy(MockTopic, bus.RetryPolicy{
+			MaxAttempts: MaxAttempts,
+		}),
+		bus.WithCircuitBreaker(bus.CircuitBreaker{
+			Window:    CircuitWindow,
+			Threshold: 0.5,
+			Cooldown:  CircuitCooldown,
+		}),
+	)
+
+	// Every first attempt fails and opens the circuit, while every retry succeeds.
+	s := &flakySubscriber{id: uuid.New(), failures: CircuitWindow, wrap: bus.Retryable}
+	bs.Subscribe(s)
+
+	for range CircuitWindow {
+		bs.Post(NewMockEmittable(MockTopic), 0)
+	}
+
+	bs.Tick()
+
+	if stats := bs.Tick(); stats.Retried != 0 || bs.Retrying() != CircuitWindow {
+		t.Fatalf("Expected %d retries held by the open circuit, found %d (%d made)\n", CircuitWindow,
+			bs.Retrying(), stats.Retried)
+	}
+
+	// The first retry is the trial that closes the circuit, after which the others are let through.
+	time.Sleep(CircuitCooldown)
+	bs.Tick()
+	bs.Tick()
+
+	if s.attempts != 2*CircuitWindow {
+		t.Fatalf("Expected %d attempts, received %d\n", 2*CircuitWindow, s.attempts)
+	}
+
+	if bs.Retrying() != 0 || len(failures.failures) != 0 {
+		t.Fatalf("Expected every retry to succeed, found %d pending and %v\n", bs.Retrying(), failures.failures)
+	}
+}
@@ -0,0 +1,35 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AndrewChon/banji/bus"
+)
+
+func TestFilteredSubscription(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, *MockSubscriber[*MockEmittable]](
+		bus.WithDemuxers(Demuxers),
+	)
+
+	eu := func(em *MockEmittable) bool { return strings.Contains(em.Topic(), ".eu.") }
+	deleted := func(em *MockEmittable) bool { return strings.HasSuffix(em.Topic(), ".deleted") }
+
+	euOnly := NewMockSubscriber[*MockEmittable]("orders.>")
+	euOrDeleted := NewMockSubscriber[*MockEmittable]("orders.>")
+	neitherEuNorDeleted := NewMockSubscriber[*MockEmittable]("orders.>")
+
+	bs.Subscribe(euOnly, eu)
+	bs.Subscribe(euOrDeleted, bus.Any(eu, deleted))
+	bs.Subscribe(neitherEuNorDeleted, bus.Not(eu), bus.Not(deleted))
+
+	bs.Post(NewMockEmittable("orders.eu.created"), 0)
+	bs.Post(NewMockEmittable("orders.eu.deleted"), 0)
+	bs.Post(NewMockEmittable("orders.us.created"), 0)
+	bs.Post(NewMockEmittable("orders.us.deleted"), 0)
+	bs.Tick()
+
+	expectHandled(t, euOnly, 2)
+	expectHandled(t, euOrDeleted, 3)
+	expectHandled(t, neitherEuNorDeleted, 1)
+}
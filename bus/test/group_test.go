@@ -0,0 +1,119 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji/bus"
+)
+
+const (
+	GroupTopic = "mock.grouped"
+	Group      = "grouped"
+)
+
+func TestQueueGroups(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, *MockSubscriber[*MockEmittable]](
+		bus.WithDemuxers(Demuxers),
+		bus.WithQueueGroup(Group, "mock.>"),
+	)
+
+	s := NewMockSubscriber[*MockEmittable](MockTopic)
+	grouped := NewMockSubscriber[*MockEmittable](GroupTopic)
+	bs.Subscribe(s)
+	bs.Subscribe(grouped)
+
+	bs.Post(NewMockEmittable(MockTopic), 0)
+	bs.Post(NewMockEmittable(GroupTopic), 0)
+
+	// Ticking the default group must leave the Emittable types of other groups queued.
+	if stats := bs.Tick(); stats.Group != bus.DefaultGroup || stats.Dispatched != 1 {
+		t.Fatalf("Expected one emittable to be dispatched by the default group, received %+v\n", stats)
+	}
+
+	if s.Handled() != 1 || grouped.Handled() != 0 {
+		t.Fatalf("Expected only the default group to be handled, received %d and %d\n", s.Handled(), grouped.Handled())
+	}
+
+	if stats := bs.TickGroup(Group); stats.Group != Group || stats.Dispatched != 1 {
+		t.Fatalf("Expected one emittable to be dispatched by %q, received %+v\n", Group, stats)
+	}
+
+	if grouped.Handled() != 1 || bs.Size() != 0 {
+		t.Fatalf("Expected the grouped emittable to be handled, received %d\n", grouped.Handled())
+	}
+}
+
+func TestQueueGroupsBoundedCache(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, *MockSubscriber[*MockEmittable]](
+		bus.WithDemuxers(Demuxers),
+		bus.WithQueueGroup(Group, "mock.>"),
+		bus.WithTopicCacheSize(1),
+	)
+
+	// Every topic evicts the group of the previous one, which must still be assigned the same way.
+	for range 2 {
+		bs.Post(NewMockEmittable(MockTopic), 0)
+		bs.Post(NewMockEmittable(GroupTopic), 0)
+	}
+
+	if stats := bs.Tick(); stats.Dispatched != 2 {
+		t.Fatalf("Expected 2 emittables to be dispatched by the default group, received %+v\n", stats)
+	}
+
+	if stats := bs.TickGroup(Group); stats.Dispatched != 2 {
+		t.Fatalf("Expected 2 emittables to be dispatched by %q, received %+v\n", Group, stats)
+	}
+}
+
+// blockingSubscriber blocks until it is released.
+type blockingSubscriber struct {
+	*MockSubscriber[*MockEmittable]
+	release chan struct{}
+}
+
+func (s *blockingSubscriber) Handle(em *MockEmittable) error {
+	<-s.release
+	return s.MockSubscriber.Handle(em)
+}
+
+func TestConcurrentQueueGroups(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, bus.Subscriber[*MockEmittable]](
+		bus.WithDemuxers(Demuxers),
+		bus.WithQueueGroup(Group, GroupTopic),
+	)
+
+	blocking := &blockingSubscriber{
+		MockSubscriber: NewMockSubscriber[*MockEmittable](GroupTopic),
+		release:        make(chan struct{}),
+	}
+
+	bs.Subscribe(blocking)
+	bs.Subscribe(NewMockSubscriber[*MockEmittable](MockTopic))
+
+	bs.Post(NewMockEmittable(GroupTopic), 0)
+	bs.Post(NewMockEmittable(MockTopic), 0)
+
+	grouped := make(chan bus.TickStats)
+	go func() {
+		grouped <- bs.TickGroup(Group)
+	}()
+
+	// A tick of the default group must not wait for the handlers of another group.
+	ticked := make(chan bus.TickStats)
+	go func() {
+		ticked <- bs.Tick()
+	}()
+
+	select {
+	case stats := <-ticked:
+		if stats.Dispatched != 1 {
+			t.Fatalf("Expected one emittable to be dispatched, received %+v\n", stats)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Tick of the default group waited for another group\n")
+	}
+
+	close(blocking.release)
+	<-grouped
+}
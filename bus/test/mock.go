@@ -8,17 +8,31 @@ import (
 	"github.com/google/uuid"
 )
 
+const MockTopic = "mock"
+
 type MockEmittable struct {
 	id       uuid.UUID
+	topic    string
 	canceled atomic.Bool
 }
 
+func NewMockEmittable(topic string) *MockEmittable {
+	return &MockEmittable{
+		id:    uuid.New(),
+		topic: topic,
+	}
+}
+
 func (e *MockEmittable) ID() uuid.UUID {
 	return e.id
 }
 
 func (e *MockEmittable) Topic() string {
-	return "mock"
+	if e.topic == "" {
+		return MockTopic
+	}
+
+	return e.topic
 }
 
 func (e *MockEmittable) Cancel() {
@@ -30,7 +44,16 @@ func (e *MockEmittable) Canceled() bool {
 }
 
 type MockSubscriber[EM bus.Emittable] struct {
-	id uuid.UUID
+	id      uuid.UUID
+	topic   string
+	handled atomic.Int64
+}
+
+func NewMockSubscriber[EM bus.Emittable](topic string) *MockSubscriber[EM] {
+	return &MockSubscriber[EM]{
+		id:    uuid.New(),
+		topic: topic,
+	}
 }
 
 func (s *MockSubscriber[EM]) ID() uuid.UUID {
@@ -38,9 +61,37 @@ func (s *MockSubscriber[EM]) ID() uuid.UUID {
 }
 
 func (s *MockSubscriber[EM]) Topic() string {
-	return "mock"
+	if s.topic == "" {
+		return MockTopic
+	}
+
+	return s.topic
 }
 
 func (s *MockSubscriber[EM]) Handle(_ EM) error {
+	s.handled.Add(1)
 	return nil
 }
+
+// Handled returns the number of times the MockSubscriber has handled an Emittable.
+func (s *MockSubscriber[EM]) Handled() int64 {
+	return s.handled.Load()
+}
+
+type MockMultiSubscriber[EM bus.Emittable] struct {
+	MockSubscriber[EM]
+	topics []string
+}
+
+func NewMockMultiSubscriber[EM bus.Emittable](topics ...string) *MockMultiSubscriber[EM] {
+	return &MockMultiSubscriber[EM]{
+		MockSubscriber: MockSubscriber[EM]{
+			id: uuid.New(),
+		},
+		topics: topics,
+	}
+}
+
+func (s *MockMultiSubscriber[EM]) Topics() []string {
+	return s.topics
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/AndrewChon/banji/bus"
+
+	"github.com/google/uuid"
+)
+
+type orderedSubscriber struct {
+	id       uuid.UUID
+	name     string
+	priority int
+	cancel   bool
+	log      *orderLog
+}
+
+func (s *orderedSubscriber) ID() uuid.UUID {
+	return s.id
+}
+
+func (s *orderedSubscriber) Topic() string {
+	return MockTopic
+}
+
+func (s *orderedSubscriber) Priority(_ string) int {
+	return s.priority
+}
+
+func (s *orderedSubscriber) Handle(em *MockEmittable) error {
+	s.log.append(s.name)
+	if s.cancel {
+		em.Cancel()
+	}
+
+	return nil
+}
+
+type orderLog struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (l *orderLog) append(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.names = append(l.names, name)
+}
+
+func TestSequentialDispatch(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, *orderedSubscriber](
+		bus.WithDemuxers(Demuxers),
+		bus.WithSequential(MockTopic),
+	)
+
+	log := new(orderLog)
+	for _, s := range []*orderedSubscriber{
+		{id: uuid.New(), name: "last", priority: 10, log: log},
+		{id: uuid.New(), name: "first", priority: -10, log: log},
+		{id: uuid.New(), name: "second", priority: 0, log: log},
+	} {
+		bs.Subscribe(s)
+	}
+
+	bs.Post(NewMockEmittable(MockTopic), 0)
+	bs.Tick()
+
+	if expected := []string{"first", "second", "last"}; !slices.Equal(log.names, expected) {
+		t.Fatalf("Expected handling order %v, received %v\n", expected, log.names)
+	}
+}
+
+func TestSequentialCancellation(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, *orderedSubscriber](
+		bus.WithDemuxers(Demuxers),
+		bus.WithSequential(MockTopic),
+	)
+
+	log := new(orderLog)
+	for _, s := range []*orderedSubscriber{
+		{id: uuid.New(), name: "interceptor", priority: -1, cancel: true, log: log},
+		{id: uuid.New(), name: "handler", priority: 0, log: log},
+	} {
+		bs.Subscribe(s)
+	}
+
+	bs.Post(NewMockEmittable(MockTopic), 0)
+
+	canceled := NewMockEmittable(MockTopic)
+	canceled.Cancel()
+	bs.Post(canceled, 0)
+
+	bs.Tick()
+
+	if expected := []string{"interceptor"}; !slices.Equal(log.names, expected) {
+		t.Fatalf("Expected handling order %v, received %v\n", expected, log.names)
+	}
+}
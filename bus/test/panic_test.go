@@ -0,0 +1,68 @@
+package test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/AndrewChon/banji/bus"
+
+	"github.com/google/uuid"
+)
+
+type panickingSubscriber struct {
+	id uuid.UUID
+}
+
+func (s *panickingSubscriber) ID() uuid.UUID {
+	return s.id
+}
+
+func (s *panickingSubscriber) Topic() string {
+	return MockTopic
+}
+
+func (s *panickingSubscriber) Handle(_ *MockEmittable) error {
+	panic("boom")
+}
+
+func TestPanicRecovery(t *testing.T) {
+	var mu sync.Mutex
+	var errs []error
+
+	bs := bus.NewBus[*MockEmittable, bus.Subscriber[*MockEmittable]](
+		bus.WithDemuxers(1),
+		bus.WithErrorBuilder(func(failure bus.Failure) bus.Emittable {
+			mu.Lock()
+			defer mu.Unlock()
+
+			errs = append(errs, failure.Err)
+			return nil
+		}),
+	)
+
+	healthy := NewMockSubscriber[*MockEmittable](MockTopic)
+	bs.Subscribe(&panickingSubscriber{id: uuid.New()})
+	bs.Subscribe(healthy)
+
+	// With a single demuxer, a leaked worker slot would deadlock the second tick.
+	for range 2 {
+		bs.Post(NewMockEmittable(MockTopic), 0)
+		bs.Tick()
+	}
+
+	expectHandled(t, healthy, 2)
+
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, received %d\n", len(errs))
+	}
+
+	var panicErr *bus.PanicError
+	if !errors.As(errs[0], &panicErr) {
+		t.Fatalf("Expected *bus.PanicError, received %v\n", errs[0])
+	}
+
+	if panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+		t.Fatalf("Expected panic value and stack trace, received %v\n", panicErr)
+	}
+}
@@ -0,0 +1,175 @@
+package test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji/bus"
+
+	"github.com/google/uuid"
+)
+
+const (
+	Partitions          = 4
+	EventsPerPartition  = 64
+	PartitionedTopic    = "partitioned"
+	PartitionedPriority = 255
+)
+
+type partitionedEmittable struct {
+	MockEmittable
+	key string
+	seq int
+}
+
+func (e *partitionedEmittable) Topic() string {
+	return PartitionedTopic
+}
+
+func (e *partitionedEmittable) PartitionKey() string {
+	return e.key
+}
+
+type partitionRecorder struct {
+	id uuid.UUID
+
+	mu   sync.Mutex
+	seqs map[string][]int
+}
+
+func (s *partitionRecorder) ID() uuid.UUID {
+	return s.id
+}
+
+func (s *partitionRecorder) Topic() string {
+	return PartitionedTopic
+}
+
+func (s *partitionRecorder) Handle(em *partitionedEmittable) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seqs[em.key] = append(s.seqs[em.key], em.seq)
+	return nil
+}
+
+func TestPartitionedOrdering(t *testing.T) {
+	bs := bus.NewBus[*partitionedEmittable, *partitionRecorder](
+		bus.WithDemuxers(Demuxers),
+	)
+
+	recorder := &partitionRecorder{
+		id:   uuid.New(),
+		seqs: make(map[string][]int),
+	}
+
+	bs.Subscribe(recorder)
+
+	// Later posts are given more urgent priorities, which would reverse their order if partitions were not respected.
+	keys := []string{"a", "b", "c", "d"}
+	for seq := range EventsPerPartition {
+		for _, key := range keys[:Partitions] {
+			bs.Post(&partitionedEmittable{key: key, seq: seq}, uint8(PartitionedPriority-seq))
+		}
+	}
+
+	bs.Tick()
+
+	for _, key := range keys[:Partitions] {
+		seqs := recorder.seqs[key]
+		if len(seqs) != EventsPerPartition || !slices.IsSorted(seqs) {
+			t.Fatalf("Partition %q was handled out of order: %v\n", key, seqs)
+		}
+	}
+}
+
+// overlapRecorder fails the first attempt to handle each Emittable with a sequence number of zero, and records whether
+// Emittable types of the same partition were ever handled concurrently.
+type overlapRecorder struct {
+	id uuid.UUID
+
+	mu       sync.Mutex
+	handling map[string]bool
+	failed   map[string]bool
+	overlaps int
+	handled  int
+}
+
+func (s *overlapRecorder) ID() uuid.UUID {
+	return s.id
+}
+
+func (s *overlapRecorder) Topic() string {
+	return PartitionedTopic
+}
+
+func (s *overlapRecorder) Handle(em *partitionedEmittable) error {
+	s.mu.Lock()
+	if s.handling[em.key] {
+		s.overlaps++
+	}
+
+	s.handling[em.key] = true
+	s.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handling[em.key] = false
+	s.handled++
+
+	if em.seq == 0 && !s.failed[em.key] {
+		s.failed[em.key] = true
+		return bus.Retryable(errFlaky)
+	}
+
+	return nil
+}
+
+func TestPartitionedRetry(t *testing.T) {
+	bs := bus.NewBus[*partitionedEmittable, *overlapRecorder](
+		bus.WithDemuxers(Demuxers),
+		bus.WithRetryPolicy(PartitionedTopic, bus.RetryPolicy{
+			MaxAttempts: 2,
+		}),
+	)
+
+	recorder := &overlapRecorder{
+		id:       uuid.New(),
+		handling: make(map[string]bool),
+		failed:   make(map[string]bool),
+	}
+
+	bs.Subscribe(recorder)
+
+	keys := []string{"a", "b", "c", "d"}
+	for _, key := range keys[:Partitions] {
+		bs.Post(&partitionedEmittable{key: key}, 0)
+	}
+
+	bs.Tick()
+
+	// The retries are due on the same tick as the Emittable types posted now to the same partitions.
+	for seq := 1; seq < EventsPerPartition/8; seq++ {
+		for _, key := range keys[:Partitions] {
+			bs.Post(&partitionedEmittable{key: key, seq: seq}, 0)
+		}
+	}
+
+	stats := bs.Tick()
+	if stats.Retried != Partitions {
+		t.Fatalf("Expected %d retries, received %d\n", Partitions, stats.Retried)
+	}
+
+	if recorder.overlaps != 0 {
+		t.Fatalf("Partitions were handled concurrently %d times\n", recorder.overlaps)
+	}
+
+	if expected := Partitions * (EventsPerPartition/8 + 1); recorder.handled != expected {
+		t.Fatalf("Expected %d attempts, received %d\n", expected, recorder.handled)
+	}
+}
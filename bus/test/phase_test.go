@@ -0,0 +1,121 @@
+package test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji/bus"
+
+	"github.com/google/uuid"
+)
+
+const (
+	UpdateTopic = "mock.update"
+	CommitTopic = "mock.commit"
+)
+
+// funcSubscriber handles every Emittable posted to its topic with a function.
+type funcSubscriber struct {
+	id    uuid.UUID
+	topic string
+	fn    func(em *MockEmittable)
+}
+
+func newFuncSubscriber(topic string, fn func(em *MockEmittable)) *funcSubscriber {
+	return &funcSubscriber{
+		id:    uuid.New(),
+		topic: topic,
+		fn:    fn,
+	}
+}
+
+func (s *funcSubscriber) ID() uuid.UUID {
+	return s.id
+}
+
+func (s *funcSubscriber) Topic() string {
+	return s.topic
+}
+
+func (s *funcSubscriber) Handle(em *MockEmittable) error {
+	s.fn(em)
+	return nil
+}
+
+func TestPhases(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, *funcSubscriber](
+		bus.WithDemuxers(Demuxers),
+		bus.WithPhase("update", UpdateTopic),
+		bus.WithPhase("commit", CommitTopic),
+	)
+
+	log := new(orderLog)
+
+	// The default phase is slow, but must still complete before the update phase begins.
+	bs.Subscribe(newFuncSubscriber(MockTopic, func(_ *MockEmittable) {
+		time.Sleep(10 * time.Millisecond)
+		log.append("input")
+	}))
+
+	bs.Subscribe(newFuncSubscriber(UpdateTopic, func(_ *MockEmittable) {
+		log.append("update")
+		bs.Post(NewMockEmittable(CommitTopic), 0)
+	}))
+
+	bs.Subscribe(newFuncSubscriber(CommitTopic, func(_ *MockEmittable) {
+		log.append("commit")
+
+		// Posting to an earlier phase must wait for the next tick.
+		if len(log.names) == 3 {
+			bs.Post(NewMockEmittable(UpdateTopic), 0)
+		}
+	}))
+
+	bs.Post(NewMockEmittable(UpdateTopic), 0)
+	bs.Post(NewMockEmittable(MockTopic), 255)
+
+	bs.Tick()
+
+	if expected := []string{"input", "update", "commit"}; !slices.Equal(log.names, expected) {
+		t.Fatalf("Expected handling order %v, received %v\n", expected, log.names)
+	}
+
+	if pending := bs.Pending()[UpdateTopic]; pending != 1 {
+		t.Fatalf("Expected 1 pending emittable on %q, received %d\n", UpdateTopic, pending)
+	}
+
+	bs.Tick()
+
+	if expected := []string{"input", "update", "commit", "update", "commit"}; !slices.Equal(log.names, expected) {
+		t.Fatalf("Expected handling order %v, received %v\n", expected, log.names)
+	}
+}
+
+func TestPhasesBoundedCache(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, *funcSubscriber](
+		bus.WithDemuxers(Demuxers),
+		bus.WithPhase("update", UpdateTopic),
+		bus.WithPhase("commit", CommitTopic),
+		bus.WithTopicCacheSize(1),
+	)
+
+	log := new(orderLog)
+	for _, topic := range []string{MockTopic, UpdateTopic, CommitTopic} {
+		bs.Subscribe(newFuncSubscriber(topic, func(em *MockEmittable) { log.append(em.Topic()) }))
+	}
+
+	// Every topic evicts the phase of the previous one, which must still be assigned the same way.
+	for range 2 {
+		bs.Post(NewMockEmittable(CommitTopic), 0)
+		bs.Post(NewMockEmittable(UpdateTopic), 0)
+		bs.Post(NewMockEmittable(MockTopic), 0)
+	}
+
+	bs.Tick()
+
+	expected := []string{MockTopic, MockTopic, UpdateTopic, UpdateTopic, CommitTopic, CommitTopic}
+	if !slices.Equal(log.names, expected) {
+		t.Fatalf("Expected handling order %v, received %v\n", expected, log.names)
+	}
+}
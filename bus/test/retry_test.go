@@ -0,0 +1,222 @@
+package test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji/bus"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaxAttempts = 3
+)
+
+var (
+	errFlaky = errors.New("flaky subscriber")
+)
+
+type flakySubscriber struct {
+	id       uuid.UUID
+	failures int
+	wrap     func(error) error
+
+	mu       sync.Mutex
+	attempts int
+}
+
+func (s *flakySubscriber) ID() uuid.UUID {
+	return s.id
+}
+
+func (s *flakySubscriber) Topic() string {
+	return MockTopic
+}
+
+func (s *flakySubscriber) Handle(_ *MockEmittable) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts++
+	if s.attempts > s.failures {
+		return nil
+	}
+
+	return s.wrap(errFlaky)
+}
+
+type failureRecorder struct {
+	mu       sync.Mutex
+	failures []bus.Failure
+}
+
+func (r *failureRecorder) build(failure bus.Failure) bus.Emittable {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures = append(r.failures, failure)
+	return nil
+}
+
+func TestRetry(t *testing.T) {
+	cases := []struct {
+		name             string
+		failures         int
+		wrap             func(error) error
+		expectedAttempts int
+		expectedFailure  int // The attempt at which a failure is reported, or zero if none is.
+	}{
+		{name: "recovers", failures: MaxAttempts - 1, wrap: bus.Retryable, expectedAttempts: MaxAttempts},
+		{name: "exhausted", failures: MaxAttempts, wrap: bus.Retryable, expectedAttempts: MaxAttempts,
+			expectedFailure: MaxAttempts},
+		{name: "permanent", failures: MaxAttempts, wrap: bus.Permanent, expectedAttempts: 1, expectedFailure: 1},
+		{name: "unmarked", failures: MaxAttempts, wrap: func(err error) error { return err }, expectedAttempts: 1,
+			expectedFailure: 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recorder := new(failureRecorder)
+			bs := bus.NewBus[*MockEmittable, *flakySubscriber](
+				bus.WithDemuxers(Demuxers),
+				bus.WithErrorBuilder(recorder.build),
+				bus.WithRetryPolicy(MockTopic, bus.RetryPolicy{
+					MaxAttempts:   MaxAttempts,
+					Backoff:       bus.FixedTickBackoff(1),
+					RetryableOnly: true,
+				}),
+			)
+
+			s := &flakySubscriber{id: uuid.New(), failures: c.failures, wrap: c.wrap}
+			bs.Subscribe(s)
+			bs.Post(NewMockEmittable(MockTopic), 0)
+
+			for range MaxAttempts + 1 {
+				bs.Tick()
+			}
+
+			if s.attempts != c.expectedAttempts {
+				t.Fatalf("Expected %d attempts, received %d\n", c.expectedAttempts, s.attempts)
+			}
+
+			if c.expectedFailure == 0 {
+				if len(recorder.failures) != 0 {
+					t.Fatalf("Expected no failures, received %v\n", recorder.failures)
+				}
+
+				return
+			}
+
+			if len(recorder.failures) != 1 || recorder.failures[0].Attempt != c.expectedFailure {
+				t.Fatalf("Expected a single failure at attempt %d, received %v\n", c.expectedFailure, recorder.failures)
+			}
+
+			if bs.Retrying() != 0 {
+				t.Fatalf("Expected no pending retries, found %d\n", bs.Retrying())
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := bus.ExponentialTickBackoff(1, 8, 0)
+
+	for attempt, expected := range map[int]uint64{2: 1, 3: 2, 4: 4, 5: 8, 6: 8} {
+		if delay := backoff.Delay(attempt); delay.Ticks != expected {
+			t.Errorf("Expected %d ticks before attempt %d, received %d\n", expected, attempt, delay.Ticks)
+		}
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	recorder := new(failureRecorder)
+	bs := bus.NewBus[*MockEmittable, *flakySubscriber](
+		bus.WithDemuxers(Demuxers),
+		bus.WithErrorBuilder(recorder.build),
+		bus.WithRetryPolicy(MockTopic, bus.RetryPolicy{
+			MaxAttempts: MaxAttempts,
+		}),
+	)
+
+	s := &flakySubscriber{id: uuid.New(), failures: MaxAttempts, wrap: bus.Retryable}
+	bs.Subscribe(s)
+
+	em := NewMockEmittable(MockTopic)
+	bs.Post(em, 0)
+	bs.Tick()
+
+	em.Cancel()
+	bs.Tick()
+
+	if s.attempts != 1 {
+		t.Fatalf("Expected a single attempt, received %d\n", s.attempts)
+	}
+
+	if len(recorder.failures) != 1 || recorder.failures[0].Attempt != 1 || !errors.Is(recorder.failures[0].Err, errFlaky) {
+		t.Fatalf("Expected the first attempt to be reported, received %v\n", recorder.failures)
+	}
+
+	if bs.Retrying() != 0 {
+		t.Fatalf("Expected no pending retries, found %d\n", bs.Retrying())
+	}
+}
+
+func TestNextRetry(t *testing.T) {
+	const (
+		retryTicks   = 3
+		tickDuration = time.Hour
+	)
+
+	cases := []struct {
+		name      string
+		opts      []bus.Option
+		reachable bool // Whether the retry is reported right after the first attempt.
+	}{
+		{name: "ticks", reachable: false},
+		{name: "tick duration", opts: []bus.Option{bus.WithTickDuration(bus.DefaultGroup, tickDuration)},
+			reachable: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			bs := bus.NewBus[*MockEmittable, *flakySubscriber](append(c.opts,
+				bus.WithDemuxers(Demuxers),
+				bus.WithRetryPolicy(MockTopic, bus.RetryPolicy{
+					MaxAttempts: MaxAttempts,
+					Backoff:     bus.FixedTickBackoff(retryTicks),
+				}),
+			)...)
+
+			bs.Subscribe(&flakySubscriber{id: uuid.New(), failures: MaxAttempts, wrap: bus.Retryable})
+			bs.Post(NewMockEmittable(MockTopic), 0)
+
+			begin := time.Now()
+			bs.Tick()
+
+			due, ok := bs.NextRetry(bus.DefaultGroup)
+			if ok != c.reachable {
+				t.Fatalf("Expected the retry to be reported: %v, received %v\n", c.reachable, ok)
+			}
+
+			if !c.reachable {
+				// The retry becomes reachable once the tick before it is due has begun.
+				for range retryTicks - 1 {
+					bs.Tick()
+				}
+
+				if _, ok := bs.NextRetry(bus.DefaultGroup); !ok {
+					t.Fatalf("Expected the retry to be reported on the tick before it is due\n")
+				}
+
+				return
+			}
+
+			if wait := due.Sub(begin); wait < retryTicks*tickDuration {
+				t.Fatalf("Expected the retry to be due after %v, due after %v\n", retryTicks*tickDuration, wait)
+			}
+		})
+	}
+}
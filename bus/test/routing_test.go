@@ -0,0 +1,120 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/AndrewChon/banji/bus"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		topic   string
+		want    bool
+	}{
+		{pattern: "banji.start", topic: "banji.start", want: true},
+		{pattern: "banji.start", topic: "banji.stop", want: false},
+		{pattern: "banji.*", topic: "banji.start", want: true},
+		{pattern: "banji.*", topic: "banji.start.extra", want: false},
+		{pattern: "banji.*", topic: "banji", want: false},
+		{pattern: "*.start", topic: "banji.start", want: true},
+		{pattern: "orders.>", topic: "orders.created", want: true},
+		{pattern: "orders.>", topic: "orders.eu.created", want: true},
+		{pattern: "orders.>", topic: "orders", want: false},
+		{pattern: ">", topic: "anything.at.all", want: true},
+		{pattern: "orders.>.created", topic: "orders.eu.created", want: false},
+		{pattern: "orders..created", topic: "orders..created", want: false},
+	}
+
+	for _, c := range cases {
+		if got := bus.Match(c.pattern, c.topic); got != c.want {
+			t.Errorf("Match(%q, %q) = %v, expected %v\n", c.pattern, c.topic, got, c.want)
+		}
+	}
+}
+
+func TestWildcardRouting(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, *MockSubscriber[*MockEmittable]](
+		bus.WithDemuxers(Demuxers),
+	)
+
+	exact := NewMockSubscriber[*MockEmittable]("orders.eu.created")
+	single := NewMockSubscriber[*MockEmittable]("orders.*.created")
+	multi := NewMockSubscriber[*MockEmittable]("orders.>")
+	unrelated := NewMockSubscriber[*MockEmittable]("users.>")
+
+	bs.Subscribe(exact)
+	bs.Subscribe(single)
+	bs.Subscribe(multi)
+	bs.Subscribe(unrelated)
+
+	bs.Post(NewMockEmittable("orders.eu.created"), 0)
+	bs.Post(NewMockEmittable("orders.us.created"), 0)
+	bs.Post(NewMockEmittable("orders.eu.deleted"), 0)
+	bs.Tick()
+
+	expectHandled(t, exact, 1)
+	expectHandled(t, single, 2)
+	expectHandled(t, multi, 3)
+	expectHandled(t, unrelated, 0)
+
+	bs.Unsubscribe(multi)
+	bs.Post(NewMockEmittable("orders.eu.created"), 0)
+	bs.Tick()
+
+	expectHandled(t, exact, 2)
+	expectHandled(t, single, 3)
+	expectHandled(t, multi, 3)
+}
+
+func TestMultiTopicRouting(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, bus.Subscriber[*MockEmittable]](
+		bus.WithDemuxers(Demuxers),
+	)
+
+	multi := NewMockMultiSubscriber[*MockEmittable]("banji.start", "banji.stop", "orders.>", "orders.eu.created")
+	bs.Subscribe(multi)
+
+	bs.Post(NewMockEmittable("banji.start"), 0)
+	bs.Post(NewMockEmittable("banji.stop"), 0)
+	bs.Post(NewMockEmittable("orders.eu.created"), 0) // Matches two patterns, but must only be handled once.
+	bs.Post(NewMockEmittable("users.created"), 0)
+	bs.Tick()
+
+	expectHandled(t, &multi.MockSubscriber, 3)
+
+	bs.Unsubscribe(multi)
+	bs.Post(NewMockEmittable("banji.start"), 0)
+	bs.Post(NewMockEmittable("orders.us.created"), 0)
+	bs.Tick()
+
+	expectHandled(t, &multi.MockSubscriber, 3)
+}
+
+func TestBoundedRouteCache(t *testing.T) {
+	bs := bus.NewBus[*MockEmittable, *MockSubscriber[*MockEmittable]](
+		bus.WithDemuxers(Demuxers),
+		bus.WithTopicCacheSize(1),
+	)
+
+	single := NewMockSubscriber[*MockEmittable]("orders.*.created")
+	bs.Subscribe(single)
+
+	// Every topic evicts the route of the previous one, which must still be routed the same way.
+	for range 2 {
+		bs.Post(NewMockEmittable("orders.eu.created"), 0)
+		bs.Post(NewMockEmittable("orders.us.created"), 0)
+		bs.Post(NewMockEmittable("users.created"), 0)
+		bs.Tick()
+	}
+
+	expectHandled(t, single, 4)
+}
+
+func expectHandled[EM bus.Emittable](t *testing.T, s *MockSubscriber[EM], want int64) {
+	t.Helper()
+
+	if got := s.Handled(); got != want {
+		t.Fatalf("Subscriber %q handled %d emittables, expected %d\n", s.Topic(), got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package bus
+
+import (
+	"strings"
+)
+
+const (
+	// TopicSeparator separates the segments of a hierarchical topic.
+	TopicSeparator = "."
+
+	// SingleWildcard matches exactly one segment of a topic (e.g. "banji.*" matches "banji.start", but not
+	// "banji.start.extra").
+	SingleWildcard = "*"
+
+	// MultiWildcard matches one or more trailing segments of a topic (e.g. "orders.>" matches "orders.created" and
+	// "orders.eu.created", but not "orders"). It may only appear as the last segment of a pattern.
+	MultiWildcard = ">"
+)
+
+// ValidPattern reports whether pattern is a well-formed subscription pattern. A pattern is well-formed if it is not
+// empty, none of its segments are empty, and MultiWildcard only appears as its final segment.
+func ValidPattern(pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+
+	segments := splitTopic(pattern)
+	for i, segment := range segments {
+		if segment == "" {
+			return false
+		}
+
+		if segment == MultiWildcard && i != len(segments)-1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Match reports whether topic is matched by pattern. Wildcards in topic are treated as literal segments.
+func Match(pattern, topic string) bool {
+	if !ValidPattern(pattern) || topic == "" {
+		return false
+	}
+
+	patternSegments := splitTopic(pattern)
+	topicSegments := splitTopic(topic)
+
+	for i, segment := range patternSegments {
+		if segment == MultiWildcard {
+			return len(topicSegments) > i
+		}
+
+		if i >= len(topicSegments) {
+			return false
+		}
+
+		if segment != SingleWildcard && segment != topicSegments[i] {
+			return false
+		}
+	}
+
+	return len(patternSegments) == len(topicSegments)
+}
+
+func splitTopic(topic string) []string {
+	return strings.Split(topic, TopicSeparator)
+}
@@ -0,0 +1,113 @@
+package bus
+
+// A topicTrie indexes values by hierarchical topic patterns, allowing every value whose pattern matches a given topic
+// to be found without scanning unrelated patterns. It is not concurrency-safe.
+type topicTrie[T any] struct {
+	root *trieNode[T]
+}
+
+type trieNode[T any] struct {
+	children map[string]*trieNode[T]
+	values   []T
+}
+
+func newTopicTrie[T any]() *topicTrie[T] {
+	return &topicTrie[T]{
+		root: newTrieNode[T](),
+	}
+}
+
+func newTrieNode[T any]() *trieNode[T] {
+	return &trieNode[T]{
+		children: make(map[string]*trieNode[T]),
+	}
+}
+
+// insert indexes v under pattern. The pattern is assumed to be valid.
+func (t *topicTrie[T]) insert(pattern string, v T) {
+	node := t.root
+	for _, segment := range splitTopic(pattern) {
+		child, ok := node.children[segment]
+		if !ok {
+			child = newTrieNode[T]()
+			node.children[segment] = child
+		}
+
+		node = child
+	}
+
+	node.values = append(node.values, v)
+}
+
+// remove removes every value indexed under pattern for which matches returns true. Branches left empty are pruned.
+func (t *topicTrie[T]) remove(pattern string, matches func(T) bool) {
+	t.root.remove(splitTopic(pattern), matches)
+}
+
+// match calls fn for every value whose pattern matches topic. A value indexed under several matching patterns will be
+// visited once per pattern.
+func (t *topicTrie[T]) match(topic string, fn func(T)) {
+	t.root.match(splitTopic(topic), fn)
+}
+
+func (n *trieNode[T]) remove(segments []string, matches func(T) bool) (empty bool) {
+	if len(segments) == 0 {
+		kept := n.values[:0]
+		for _, v := range n.values {
+			if !matches(v) {
+				kept = append(kept, v)
+			}
+		}
+
+		// Clear the tail so removed values can be garbage collected.
+		clear(n.values[len(kept):])
+		n.values = kept
+
+		return n.empty()
+	}
+
+	child, ok := n.children[segments[0]]
+	if !ok {
+		return n.empty()
+	}
+
+	if child.remove(segments[1:], matches) {
+		delete(n.children, segments[0])
+	}
+
+	return n.empty()
+}
+
+func (n *trieNode[T]) match(segments []string, fn func(T)) {
+	if len(segments) == 0 {
+		for _, v := range n.values {
+			fn(v)
+		}
+
+		return
+	}
+
+	// A multi-segment wildcard consumes every remaining segment, provided there is at least one.
+	if multi, ok := n.children[MultiWildcard]; ok {
+		for _, v := range multi.values {
+			fn(v)
+		}
+	}
+
+	if single, ok := n.children[SingleWildcard]; ok {
+		single.match(segments[1:], fn)
+	}
+
+	// Wildcard segments in the topic itself are literal, so they must not be visited twice.
+	if segments[0] == SingleWildcard || segments[0] == MultiWildcard {
+		return
+	}
+
+	if exact, ok := n.children[segments[0]]; ok {
+		exact.match(segments[1:], fn)
+	}
+}
+
+func (n *trieNode[T]) empty() bool {
+	return len(n.values) == 0 && len(n.children) == 0
+}
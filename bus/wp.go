@@ -7,7 +7,6 @@ import (
 // A workerPool manages a bounded number of workers that execute tasks in a concurrent fashion.
 type workerPool struct {
 	sema chan struct{}
-	wg   sync.WaitGroup
 }
 
 func newWorkerPool(n int) *workerPool {
@@ -16,19 +15,16 @@ func newWorkerPool(n int) *workerPool {
 	}
 }
 
-// post submits a task for execution.
-func (p *workerPool) post(task func()) {
-	p.wg.Add(1)
+// post submits a task for execution, tracking it with wg so that the caller can wait for it to complete.
+func (p *workerPool) post(wg *sync.WaitGroup, task func()) {
+	wg.Add(1)
 	p.sema <- struct{}{}
 
 	go func() {
+		// The worker must be released even if the task panics.
+		defer wg.Done()
+		defer func() { <-p.sema }()
+
 		task()
-		<-p.sema
-		p.wg.Done()
 	}()
 }
-
-// wait blocks until all tasks posted to the workerPool have been completed.
-func (p *workerPool) wait() {
-	p.wg.Wait()
-}
@@ -0,0 +1,95 @@
+package banji
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// A ShutdownComponent is a Component that takes part in shutting down the engine. Shutdown is called once for each
+// ShutdownPhase, and returns once the Component is done with that phase. A Component that needs the engine to keep
+// ticking before the next phase begins, such as to flush Events it has buffered, may request a number of additional
+// ticks.
+type ShutdownComponent interface {
+	Component
+	Shutdown(ctx context.Context, phase ShutdownPhase) (ticks int, err error)
+}
+
+// A DependentComponent is a Component that depends on other Components. It is shut down before every Component it
+// depends on. Components are compared with ==, and dependencies that were not passed to WithComponents are ignored.
+// Dependencies must therefore be of comparable types, such as pointers.
+type DependentComponent interface {
+	Component
+	DependsOn() []Component
+}
+
+// shutdownOrder returns the ShutdownComponent types among components in reverse dependency order, so that every
+// Component is shut down before the Components it depends on. Components that do not depend on each other are shut
+// down in the reverse of the order they were registered in, like deferred calls. Components are tracked by their
+// position rather than used as map keys, so that Components of types that cannot be hashed are accepted.
+func shutdownOrder(components []Component) ([]ShutdownComponent, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	involved := slices.ContainsFunc(components, func(c Component) bool {
+		_, shuts := c.(ShutdownComponent)
+		_, depends := c.(DependentComponent)
+		return shuts || depends
+	})
+
+	if !involved {
+		return nil, nil
+	}
+
+	state := make([]int, len(components))
+
+	// Components are visited depth-first, dependencies first, which yields the order they must be started in.
+	var order []ShutdownComponent
+	var visit func(i int) error
+	visit = func(i int) error {
+		c := components[i]
+
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("component %T depends on itself", c)
+		case visited:
+			return nil
+		}
+
+		state[i] = visiting
+
+		if dc, ok := c.(DependentComponent); ok {
+			for _, dep := range dc.DependsOn() {
+				j := slices.Index(components, dep)
+				if j < 0 {
+					continue
+				}
+
+				if err := visit(j); err != nil {
+					return fmt.Errorf("%w (via %T)", err, c)
+				}
+			}
+		}
+
+		state[i] = visited
+
+		if sc, ok := c.(ShutdownComponent); ok {
+			order = append(order, sc)
+		}
+
+		return nil
+	}
+
+	for i := range components {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.Reverse(order)
+
+	return order, nil
+}
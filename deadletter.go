@@ -0,0 +1,76 @@
+package banji
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/AndrewChon/gsync"
+)
+
+// A deadLetterCounter counts the Events that could not be delivered, per topic.
+type deadLetterCounter struct {
+	counts gsync.Map[string, *atomic.Uint64]
+}
+
+func (c *deadLetterCounter) add(topic string) {
+	count, ok := c.counts.Load(topic)
+	if !ok {
+		count, _ = c.counts.LoadOrStore(topic, new(atomic.Uint64))
+	}
+
+	count.Add(1)
+}
+
+func (c *deadLetterCounter) load(topic string) uint64 {
+	count, ok := c.counts.Load(topic)
+	if !ok {
+		return 0
+	}
+
+	return count.Load()
+}
+
+func (c *deadLetterCounter) snapshot() map[string]uint64 {
+	snapshot := make(map[string]uint64)
+	c.counts.Range(func(topic string, count *atomic.Uint64) bool {
+		snapshot[topic] = count.Load()
+		return true
+	})
+
+	return snapshot
+}
+
+// DeadLetters returns the number of Events that could not be delivered, per topic.
+func (eng *Engine) DeadLetters() map[string]uint64 {
+	return eng.deadLetters.snapshot()
+}
+
+// DeadLetterCount returns the number of Events posted to topic that could not be delivered.
+func (eng *Engine) DeadLetterCount(topic string) uint64 {
+	return eng.deadLetters.load(topic)
+}
+
+// deadLetter counts an Event that could not be delivered and posts a DeadLetterEvent wrapping it. DeadLetterEvent
+// types are routed while the engine is active, even if it has stopped accepting new Events, so that Events dropped
+// while the engine drains are still reported.
+func (eng *Engine) deadLetter(event Event, reason error) {
+	if _, ok := event.(broadcast); ok {
+		return
+	}
+
+	eng.deadLetters.add(event.Topic())
+
+	if !eng.active.Load() {
+		return
+	}
+
+	deadLetter := &DeadLetterEvent{
+		event:  event,
+		reason: reason,
+	}
+
+	// The dead letter keeps the values of the Event's context, but must not be dropped along with it.
+	ctx := context.WithoutCancel(event.Context())
+	deadLetter.mark(ctx)
+	eng.bus.PostContext(ctx, deadLetter, 0)
+}
@@ -1,6 +1,8 @@
 package banji
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -11,27 +13,96 @@ import (
 
 // The Engine brokers communication between decoupled components via Event and Receiver.
 type Engine struct {
-	options   *Options
-	bus       Bus
-	active    atomic.Bool
-	accepting atomic.Bool
-	ticker    *time.Ticker
-	loopWg    sync.WaitGroup
-	stopLoop  chan struct{}
+	options     *Options
+	bus         Bus
+	scheduler   *scheduler
+	deadLetters deadLetterCounter
+	errors      *errorGuard
+	stopOrder   []ShutdownComponent
+	schedules   []*tickSchedule
+	reports     []ComponentShutdown
+	reportsMu   sync.Mutex
+	active      atomic.Bool
+	accepting   atomic.Bool
+	paused      atomic.Bool
+	tickMu      sync.Mutex
+	prevTick    time.Time
+	ticks       atomic.Uint64
+	dropped     atomic.Uint64
+	lastTick    atomic.Pointer[bus.TickStats]
+	ticked      chan struct{}
+	wake        chan struct{}
+	rearm       chan struct{}
+	ticker      *time.Ticker
+	loopWg      sync.WaitGroup
+	stopLoop    chan struct{}
+
+	// ctx is handed to every ContextReceiver, and is canceled once the engine has stopped.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func New(opts ...Option) *Engine {
 	eng := &Engine{
-		options:  NewOptions(opts...),
-		stopLoop: make(chan struct{}, 1),
+		options:   NewOptions(opts...),
+		scheduler: newScheduler(),
+		ticked:    make(chan struct{}, 1),
+		wake:      make(chan struct{}, 1),
+		rearm:     make(chan struct{}, 1),
+		stopLoop:  make(chan struct{}),
 	}
 
-	eng.bus = bus.NewBus[Event, Receiver](
+	eng.ctx, eng.cancel = context.WithCancel(context.Background())
+	eng.errors = newErrorGuard(eng.options.MaxErrorDepth, eng.options.ErrorWindow)
+
+	busOpts := []bus.Option{
 		bus.WithDemuxers(eng.options.Demuxers),
-		bus.WithErrorBuilder(errorBuilder),
-	)
+		bus.WithErrorBuilder(eng.buildError),
+		bus.WithDropHandler(eng.drop),
+		bus.WithRejectHandler(eng.reject),
+		bus.WithSequential(eng.options.Sequential...),
+		bus.WithRepanic(eng.options.Repanic),
+		bus.WithContext(eng.ctx),
+		bus.WithHandlerTimeout(eng.options.HandlerTimeout),
+		bus.WithTickBudget(eng.options.TickBudget),
+	}
+
+	if eng.options.Tickless {
+		busOpts = append(busOpts,
+			bus.WithPostHook(eng.notify),
+			bus.WithTickDuration(bus.DefaultGroup, time.Second/time.Duration(eng.options.TPS)),
+		)
+	}
+
+	schedules, scheduleOpts, err := newTickSchedules(eng.options.Schedules)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load schedules: %v\n", err))
+	}
+
+	eng.schedules = schedules
+	busOpts = append(busOpts, scheduleOpts...)
+
+	for _, phase := range eng.options.Phases {
+		busOpts = append(busOpts, bus.WithPhase(phase.Name, phase.Patterns...))
+	}
+
+	for _, rp := range eng.options.RetryPolicies {
+		busOpts = append(busOpts, bus.WithRetryPolicy(rp.Pattern, rp.Policy))
+	}
+
+	if eng.options.CircuitBreaker != nil {
+		busOpts = append(busOpts,
+			bus.WithCircuitBreaker(*eng.options.CircuitBreaker),
+			bus.WithCircuitBuilder(circuitBuilder),
+		)
+	}
 
-	eng.ticker = time.NewTicker((1 * time.Second) / time.Duration(eng.options.TPS))
+	eng.bus = bus.NewBus[Event, Receiver](busOpts...)
+
+	// A tickless engine sleeps until it is woken up, so it has no use for a ticker.
+	if !eng.options.Tickless {
+		eng.ticker = time.NewTicker((1 * time.Second) / time.Duration(eng.options.TPS))
+	}
 
 	for _, c := range eng.options.Components {
 		rs, err := c.Bootstrap()
@@ -45,6 +116,13 @@ func New(opts ...Option) *Engine {
 		}
 	}
 
+	stopOrder, err := shutdownOrder(eng.options.Components)
+	if err != nil {
+		panic(fmt.Sprintf("failed to order components: %v\n", err))
+	}
+
+	eng.stopOrder = stopOrder
+
 	return eng
 }
 
@@ -52,6 +130,21 @@ func (eng *Engine) Active() bool {
 	return eng.active.Load()
 }
 
+// Ticks returns the number of ticks the engine has run, including those run while draining.
+func (eng *Engine) Ticks() uint64 {
+	return eng.ticks.Load()
+}
+
+// LastTick returns statistics on the work done by the last tick the engine loop has run, or the zero value if it has
+// yet to run one.
+func (eng *Engine) LastTick() bus.TickStats {
+	if stats := eng.lastTick.Load(); stats != nil {
+		return *stats
+	}
+
+	return bus.TickStats{}
+}
+
 // Start is a non-blocking operation that starts the engine. Components can listen for StartTopic to be notified when
 // this function has been executed.
 func (eng *Engine) Start() {
@@ -64,66 +157,188 @@ func (eng *Engine) Start() {
 
 	eng.Post(new(StartEvent), 0)
 	go eng.runLoop()
+	eng.startSchedules()
 }
 
-// Stop is a blocking operation that gracefully shuts down the engine. Components can listen for StopTopic to be
-// notified when this function has been executed.
+// Stop is a blocking operation that gracefully shuts down the engine. It is equivalent to Shutdown without a deadline,
+// and will not return for as long as Receivers keep posting new Events while the engine drains.
 func (eng *Engine) Stop() {
+	_ = eng.Shutdown(context.Background())
+}
+
+// Shutdown is a blocking operation that gracefully shuts down the engine in three phases (see ShutdownPhase), calling
+// the Shutdown method of every ShutdownComponent in reverse dependency order during each of them:
+//
+//  1. During PreStopPhase, the engine keeps running as usual.
+//  2. During StopPhase, StopEvent is posted, after which the engine stops accepting new Events and keeps handling those
+//     already posted for as long as ctx allows. Components can listen for StopTopic to be notified of this phase.
+//  3. During PostStopPhase, the engine is no longer active. The context handed to ContextReceiver types has been
+//     canceled, including that of handlers which have timed out but are still running.
+//
+// Events that have yet to be handled once the engine stops draining are abandoned, along with Events waiting to be
+// retried and posts scheduled with PostAfter or PostAt. If any Event was abandoned or any ShutdownComponent failed,
+// Shutdown returns a ShutdownError, which wraps the error of ctx if the engine stopped draining early. How long each
+// ShutdownComponent took is reported by ShutdownReports. A tick that is in progress when ctx is done is allowed to
+// complete, but the context handed to ContextReceiver types is canceled immediately so that they can abort early. A
+// paused engine is resumed before it is shut down.
+func (eng *Engine) Shutdown(ctx context.Context) error {
 	if !eng.active.Load() {
-		return
+		return nil
 	}
 
+	eng.reportsMu.Lock()
+	eng.reports = nil
+	eng.reportsMu.Unlock()
+
+	eng.Resume()
+
+	// The context handed to ContextReceiver types is canceled as soon as ctx is done, even if the current tick is still
+	// in progress.
+	release := context.AfterFunc(ctx, eng.cancel)
+	defer release()
+
+	eng.awaitTicks(ctx, eng.shutdownPhase(ctx, PreStopPhase))
+
 	eng.Post(new(StopEvent), 0)
 	eng.accepting.Store(false)
 
-	eng.ticker.Stop()
+	if eng.ticker != nil {
+		eng.ticker.Stop()
+	}
+
 	eng.stopLoop <- struct{}{}
 	eng.loopWg.Wait()
+	eng.stopSchedules()
 
-	for eng.bus.Size() > 0 {
-		eng.bus.Tick()
+	extra := eng.shutdownPhase(ctx, StopPhase)
+
+	var err error
+	for ; eng.bus.Size() > 0 || extra > 0; extra-- {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+
+		eng.tickAll()
+		eng.summarizeErrors()
+		eng.ticks.Add(1)
 	}
 
+	abandoned := eng.abandon()
+
+	eng.cancel()
 	eng.active.Store(false)
+
+	eng.shutdownPhase(ctx, PostStopPhase)
+
+	failures := eng.shutdownFailures()
+	if len(abandoned) == 0 && len(failures) == 0 {
+		return nil
+	}
+
+	return &ShutdownError{
+		Abandoned: abandoned,
+		Failures:  failures,
+		Err:       err,
+	}
 }
 
-// Subscribe registers a Receiver to its associated topic. A Receiver can only be subscribed once. Subsequent calls
-// to Subscribe with the same Receiver will have no effect.
-func (eng *Engine) Subscribe(r Receiver) {
+// awaitTicks waits until the engine loop has run n more ticks, or until ctx is done.
+func (eng *Engine) awaitTicks(ctx context.Context, n int) {
+	target := eng.ticks.Load() + uint64(max(n, 0))
+	for eng.ticks.Load() < target {
+		// A tickless engine only ticks when it is woken up.
+		eng.wakeUp()
+
+		select {
+		case <-eng.ticked:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Subscribe registers a Receiver to its associated topic. Topics are dot-separated and may contain wildcards: "*"
+// matches exactly one segment and ">" matches one or more trailing segments (e.g. "banji.*" or "orders.>"). A
+// MultiReceiver is subscribed to each of its topics. A Receiver can only be subscribed once. Subsequent calls to
+// Subscribe with the same Receiver will have no effect.
+//
+// The Receiver only handles the Events accepted by every one of filters, as well as by its own Filter method if it
+// implements FilteredReceiver.
+func (eng *Engine) Subscribe(r Receiver, filters ...Filter) {
 	r.mark()
-	eng.bus.Subscribe(r)
+	eng.bus.Subscribe(r, busFilters(filters)...)
 }
 
-// Unsubscribe unregisters a Receiver. Note that this can be an expensive operation to perform; thus, it should be used
-// sparingly.
+// Unsubscribe unregisters a Receiver from every topic it was subscribed to. Note that this can be an expensive
+// operation to perform; thus, it should be used sparingly.
 func (eng *Engine) Unsubscribe(r Receiver) {
 	eng.bus.Unsubscribe(r)
 }
 
-// Post posts an Event to the engine, which will be handled on the next available tick.
+// CircuitState returns the state of the circuit of a Receiver. Circuits are always closed unless circuit breaking has
+// been enabled with WithCircuitBreaker.
+func (eng *Engine) CircuitState(r Receiver) bus.CircuitState {
+	return eng.bus.CircuitState(r.ID())
+}
+
+// Post posts an Event to the engine, which will be handled on the next available tick. The Event is posted with
+// context.Background(), even when Post is called by a handler: the engine cannot tell which Event, if any, is being
+// handled by the caller. Handlers that want the Events they post to inherit the context of the Event they handle must
+// post them with PostContext.
 func (eng *Engine) Post(event Event, priority uint8) {
-	if eng.accepting.Load() {
-		event.mark()
-		eng.bus.Post(event, priority)
+	eng.post(context.Background(), event, priority)
+}
+
+// PostContext posts an Event to the engine along with a context, which will be handled on the next available tick.
+// The context carries deadlines, cancellation and request-scoped values from the poster to every Receiver: it is handed
+// to every ContextReceiver that handles the Event and is available through Event.Context. If the context is done by the
+// time the Event is dequeued, the Event is not handled and is dead-lettered instead.
+//
+// Events posted by a handler inherit its Event's context when posted with the context returned by Event.Context. When
+// posted with the context handed to HandleContext, or one derived from it, they carry its values and any deadline the
+// handler added, and are canceled along with the handled Event's context rather than once the handler returns. Events
+// posted by the engine on behalf of an Event, such as ErrorEvent and DeadLetterEvent types, inherit its context's
+// values, but not its cancellation.
+func (eng *Engine) PostContext(ctx context.Context, event Event, priority uint8) {
+	eng.post(ctx, event, priority)
+}
+
+// post posts an Event to the engine and reports whether it was accepted.
+func (eng *Engine) post(ctx context.Context, event Event, priority uint8) bool {
+	if !eng.accepting.Load() {
+		eng.deadLetter(event, ErrNotAccepting)
+		return false
 	}
+
+	ctx = bus.Inherit(ctx)
+	event.mark(ctx)
+	eng.bus.PostContext(ctx, event, priority)
+
+	return true
 }
 
 func (eng *Engine) runLoop() {
+	switch {
+	case eng.options.Tickless:
+		eng.runTickless()
+		return
+	case eng.options.FixedTimestep:
+		eng.runFixed()
+		return
+	}
+
 	for {
 		select {
 		case tick := <-eng.ticker.C:
-			eng.loopWg.Add(1)
-
-			eng.Post(&PreTickEvent{
-				tick: tick,
-			}, 0)
-
-			eng.bus.Tick()
-
-			eng.Post(&PostTickEvent{
-				tick: tick,
-			}, 0)
+			if eng.paused.Load() {
+				continue
+			}
 
+			eng.loopWg.Add(1)
+			start := time.Now()
+			if stats, ok := eng.tick(tick, tickTiming{lag: start.Sub(tick)}); ok {
+				eng.overrun(start, stats)
+			}
 			eng.loopWg.Done()
 		case <-eng.stopLoop:
 			return
@@ -131,8 +346,110 @@ func (eng *Engine) runLoop() {
 	}
 }
 
-func errorBuilder(err error) bus.Emittable {
-	return &ErrorEvent{
-		err: err,
+// tick runs a single tick of the engine loop, unless the engine is paused, and returns statistics on the work done by
+// the bus along with whether the tick ran. Ticks never overlap, whether they are run by the engine loop or by Step.
+func (eng *Engine) tick(now time.Time, timing tickTiming) (bus.TickStats, bool) {
+	eng.tickMu.Lock()
+	defer eng.tickMu.Unlock()
+
+	// The engine may have been paused after the loop decided to tick, but before the tick could begin.
+	if eng.paused.Load() {
+		return bus.TickStats{}, false
+	}
+
+	return eng.runTick(now, timing), true
+}
+
+// runTick runs a single tick and returns statistics on the work done by the bus. Unless timing carries a delta, the
+// delta is measured since the previous tick. It must be called with tickMu held.
+func (eng *Engine) runTick(now time.Time, timing tickTiming) bus.TickStats {
+	timing.number = eng.ticks.Load() + 1
+	timing.timestep = eng.timestep()
+
+	if timing.delta == 0 && !eng.prevTick.IsZero() {
+		timing.delta = now.Sub(eng.prevTick)
+	}
+
+	eng.prevTick = now
+
+	tickEvents := !eng.options.Tickless || eng.options.TickEvents
+
+	eng.releasePending(now)
+	if tickEvents {
+		eng.Post(&PreTickEvent{
+			tickTiming: timing,
+			tick:       now,
+		}, 0)
+	}
+
+	stats := eng.bus.Tick()
+	eng.lastTick.Store(&stats)
+
+	if tickEvents {
+		eng.Post(&PostTickEvent{
+			tickTiming: timing,
+			tick:       now,
+			stats:      stats,
+		}, 0)
 	}
+
+	// Events carried over to the next tick must not wait for a tickless engine to be woken up by another post.
+	if stats.CarriedOver > 0 {
+		eng.wakeUp()
+	}
+
+	eng.summarizeErrors()
+	eng.ticks.Add(1)
+
+	select {
+	case eng.ticked <- struct{}{}:
+	default:
+	}
+
+	return stats
+}
+
+// drop is called by the bus whenever it drops an Event instead of routing it.
+func (eng *Engine) drop(em bus.Emittable, reason error) {
+	event, ok := em.(Event)
+	if !ok {
+		return
+	}
+
+	req, ok := event.(Request)
+	if ok && errors.Is(reason, bus.ErrNoSubscribers) {
+		req.Fail(fmt.Errorf("%w: %q", ErrNoResponders, req.Topic()))
+	}
+
+	eng.deadLetter(event, reason)
+}
+
+// reject is called by the bus whenever no Receiver admits an Event routed to them. Events rejected only because of
+// open circuits are dead-lettered, while those filtered out by every Receiver are not.
+func (eng *Engine) reject(em bus.Emittable, reason error) {
+	event, ok := em.(Event)
+	if !ok {
+		return
+	}
+
+	if req, ok := event.(Request); ok {
+		req.Fail(fmt.Errorf("%w: %q: %w", ErrNoEligibleResponders, req.Topic(), reason))
+	}
+
+	if errors.Is(reason, bus.ErrCircuitOpen) {
+		eng.deadLetter(event, reason)
+	}
+}
+
+func circuitBuilder(change bus.CircuitChange) bus.Emittable {
+	receiver, _ := change.Subscriber.(Receiver)
+
+	event := &CircuitEvent{
+		receiver:    receiver,
+		state:       change.State,
+		failureRate: change.FailureRate,
+	}
+
+	event.mark(context.Background())
+	return event
 }
@@ -0,0 +1,172 @@
+package banji
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/AndrewChon/banji/bus"
+
+	"github.com/google/uuid"
+)
+
+// An errorGuard keeps ErrorEvent types from cascading. It suppresses ErrorEvent types that are too deeply nested, as
+// well as repeats of identical errors within a window of ticks, and summarizes what it suppressed at the end of each
+// window.
+type errorGuard struct {
+	maxDepth int
+	window   int
+
+	mu      sync.Mutex
+	ticks   int
+	order   []errorKey
+	reports map[errorKey]*ErrorSummary
+}
+
+// An errorKey identifies errors that are considered identical.
+type errorKey struct {
+	receiver uuid.UUID
+	topic    string
+	message  string
+}
+
+func newErrorGuard(maxDepth, window int) *errorGuard {
+	return &errorGuard{
+		maxDepth: maxDepth,
+		window:   window,
+		reports:  make(map[errorKey]*ErrorSummary),
+	}
+}
+
+// admit reports whether an ErrorEvent should be posted. ErrorEvent types that are not admitted are counted towards the
+// summary of the current window.
+func (g *errorGuard) admit(event *ErrorEvent) bool {
+	key := errorKey{
+		receiver: event.ReceiverID(),
+		topic:    event.SourceTopic(),
+	}
+
+	if event.Error() != nil {
+		key.message = event.Error().Error()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	report, seen := g.reports[key]
+	if !seen {
+		report = &ErrorSummary{
+			ReceiverID:   key.receiver,
+			ReceiverType: event.ReceiverType(),
+			SourceTopic:  key.topic,
+			Message:      key.message,
+			Depth:        event.Depth(),
+		}
+
+		g.order = append(g.order, key)
+		g.reports[key] = report
+	}
+
+	// The first occurrence of an error within the window is posted, unless it is part of an error cascade.
+	if !seen && event.Depth() <= g.maxDepth {
+		return true
+	}
+
+	report.Suppressed++
+	return false
+}
+
+// tick advances the current window by a tick. At the end of a window, it returns an ErrorSummaryEvent describing the
+// errors that were suppressed during it, or nil if none were.
+func (g *errorGuard) tick() *ErrorSummaryEvent {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.ticks++
+	if g.ticks < g.window {
+		return nil
+	}
+
+	var summaries []ErrorSummary
+	for _, key := range g.order {
+		if report := g.reports[key]; report.Suppressed > 0 {
+			summaries = append(summaries, *report)
+		}
+	}
+
+	g.ticks = 0
+	g.order = nil
+	clear(g.reports)
+
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	return &ErrorSummaryEvent{
+		summaries: summaries,
+		window:    g.window,
+	}
+}
+
+// buildError builds the ErrorEvent posted when a Receiver fails, or returns nil if the ErrorEvent is suppressed.
+func (eng *Engine) buildError(failure bus.Failure) bus.Emittable {
+	// The requester is told about the failure even if the ErrorEvent is suppressed.
+	failRequest(failure)
+
+	source, _ := failure.Emittable.(Event)
+	receiver, _ := failure.Subscriber.(Receiver)
+
+	event := &ErrorEvent{
+		err:      failure.Err,
+		source:   source,
+		receiver: receiver,
+		duration: failure.Duration,
+		attempt:  failure.Attempt,
+		depth:    1,
+	}
+
+	if parent, ok := source.(*ErrorEvent); ok {
+		event.depth = parent.depth + 1
+	}
+
+	if !eng.errors.admit(event) {
+		return nil
+	}
+
+	// The bus posts the ErrorEvent with the values of its source's context, but not its cancellation.
+	ctx := context.Background()
+	if source != nil {
+		ctx = context.WithoutCancel(source.Context())
+	}
+
+	event.mark(ctx)
+	return event
+}
+
+// summarizeErrors advances the error window by a tick and posts a summary of the errors suppressed during it once it
+// is over. The summary is posted even if the engine has stopped accepting new Events, so that errors suppressed while
+// the engine drains are still reported.
+func (eng *Engine) summarizeErrors() {
+	summary := eng.errors.tick()
+	if summary == nil {
+		return
+	}
+
+	summary.mark(context.Background())
+	eng.bus.Post(summary, 0)
+}
+
+// An ErrorSummary describes an error that was suppressed one or more times during a window.
+type ErrorSummary struct {
+	ReceiverID   uuid.UUID
+	ReceiverType string
+	SourceTopic  string
+	Message      string
+	Depth        int
+	Suppressed   int
+}
+
+func (s ErrorSummary) String() string {
+	return fmt.Sprintf("%s (%s) failed on %q %d more time(s): %s", s.ReceiverType, s.ReceiverID, s.SourceTopic,
+		s.Suppressed, s.Message)
+}
@@ -0,0 +1,73 @@
+package banji
+
+import (
+	"github.com/AndrewChon/banji/bus"
+)
+
+// A Filter is a predicate that decides whether an Event should be handled by a Receiver. Filters are evaluated before
+// a handler is scheduled, so an Event that is filtered out never occupies a demuxer.
+type Filter func(event Event) bool
+
+// A FilteredReceiver is a Receiver that is only interested in a subset of the Events routed to it.
+type FilteredReceiver interface {
+	Receiver
+	Filter(event Event) bool
+}
+
+// And returns a Filter that accepts an Event only if f and every one of others accept it.
+func (f Filter) And(others ...Filter) Filter {
+	return func(event Event) bool {
+		if !f(event) {
+			return false
+		}
+
+		for _, other := range others {
+			if !other(event) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Or returns a Filter that accepts an Event if f or at least one of others accepts it.
+func (f Filter) Or(others ...Filter) Filter {
+	return func(event Event) bool {
+		if f(event) {
+			return true
+		}
+
+		for _, other := range others {
+			if other(event) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not returns a Filter that accepts an Event only if f rejects it.
+func (f Filter) Not() Filter {
+	return func(event Event) bool {
+		return !f(event)
+	}
+}
+
+// MinLogLevel returns a Filter that only accepts LogEvent types whose Log is at least as severe as level.
+func MinLogLevel(level Level) Filter {
+	return func(event Event) bool {
+		logEvent, ok := event.(*LogEvent)
+		return ok && logEvent.Log().Level() >= level
+	}
+}
+
+func busFilters(filters []Filter) []bus.Filter[Event] {
+	converted := make([]bus.Filter[Event], len(filters))
+	for i, filter := range filters {
+		converted[i] = bus.Filter[Event](filter)
+	}
+
+	return converted
+}
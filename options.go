@@ -2,6 +2,9 @@ package banji
 
 import (
 	"runtime"
+	"time"
+
+	"github.com/AndrewChon/banji/bus"
 )
 
 type Option func(*Options)
@@ -10,13 +13,34 @@ type Options struct {
 	TPS        int
 	Demuxers   int
 	Components []Component
+	Sequential []string
+	Repanic    bool
+
+	RetryPolicies  []bus.TopicRetryPolicy
+	CircuitBreaker *bus.CircuitBreaker
+	HandlerTimeout time.Duration
+	MaxErrorDepth  int
+	ErrorWindow    int
+
+	Tickless    bool
+	BatchWindow time.Duration
+	TickEvents  bool
+	TickBudget  bus.TickBudget
+
+	FixedTimestep bool
+	MaxCatchUp    int
+
+	Schedules []Schedule
+	Phases    []bus.Phase
 }
 
 func NewOptions(opts ...Option) *Options {
 	// Default settings.
 	options := &Options{
-		TPS:      128,
-		Demuxers: runtime.NumCPU(),
+		TPS:           128,
+		Demuxers:      runtime.NumCPU(),
+		MaxErrorDepth: 2,
+		ErrorWindow:   1,
 	}
 
 	for _, opt := range opts {
@@ -51,3 +75,157 @@ func WithComponents(components ...Component) Option {
 		options.Components = append(options.Components, components...)
 	}
 }
+
+// WithSequentialTopics enables sequential dispatch for every topic matched by one of patterns. Instead of being handled
+// concurrently, an Event posted to such a topic is handled by one Receiver at a time in order of priority (see
+// PrioritizedReceiver), and canceling it prevents the remaining Receivers from handling it.
+func WithSequentialTopics(patterns ...string) Option {
+	return func(options *Options) {
+		options.Sequential = append(options.Sequential, patterns...)
+	}
+}
+
+// WithRepanic determines whether a panic raised by a Receiver crashes the program. By default, the engine recovers the
+// panic and posts an ErrorEvent carrying a bus.PanicError with the panic value and stack trace; enabling repanicking is
+// mainly useful in development builds.
+func WithRepanic(enabled bool) Option {
+	return func(options *Options) {
+		options.Repanic = enabled
+	}
+}
+
+// WithRetryPolicy sets the retry policy of every topic matched by pattern. A Receiver that fails to handle an Event on
+// such a topic handles it again according to the policy, and an ErrorEvent is only posted once the policy gives up.
+// Receivers implementing RetryingReceiver may override it.
+func WithRetryPolicy(pattern string, policy bus.RetryPolicy) Option {
+	return func(options *Options) {
+		options.RetryPolicies = append(options.RetryPolicies, bus.TopicRetryPolicy{
+			Pattern: pattern,
+			Policy:  policy,
+		})
+	}
+}
+
+// WithCircuitBreaker enables circuit breaking. The engine tracks the failure rate of every Receiver and stops routing
+// Events to a Receiver whose failure rate reaches the threshold until its cooldown is over, posting a CircuitEvent
+// whenever a circuit changes state. Events that no Receiver admits only because their circuits are open are posted as
+// a DeadLetterEvent with a reason wrapping bus.ErrCircuitOpen.
+func WithCircuitBreaker(cb bus.CircuitBreaker) Option {
+	return func(options *Options) {
+		options.CircuitBreaker = &cb
+	}
+}
+
+// WithHandlerTimeout sets the default handler timeout. A handler that does not return before its timeout is reported
+// through an ErrorEvent with an error wrapping bus.ErrHandlerTimeout, and the context handed to it, if it is a
+// ContextReceiver, is canceled. Receivers implementing TimedReceiver may override it.
+func WithHandlerTimeout(d time.Duration) Option {
+	return func(options *Options) {
+		options.HandlerTimeout = d
+	}
+}
+
+// WithMaxErrorDepth sets how deeply ErrorEvent types may be nested before they are suppressed. An error raised while
+// handling an ordinary Event has a depth of one; an error raised while handling that ErrorEvent has a depth of two, and
+// so on. Suppressed ErrorEvent types are reported through an ErrorSummaryEvent instead.
+func WithMaxErrorDepth(n int) Option {
+	if n < 1 {
+		n = 1
+	}
+
+	return func(options *Options) {
+		options.MaxErrorDepth = n
+	}
+}
+
+// WithErrorWindow sets the length, in ticks, of the window within which identical errors are only reported once. An
+// error is identical to another if it was returned by the same Receiver, for an Event of the same topic, with the same
+// message. Repeats are suppressed and reported through an ErrorSummaryEvent at the end of the window.
+func WithErrorWindow(ticks int) Option {
+	if ticks < 1 {
+		ticks = 1
+	}
+
+	return func(options *Options) {
+		options.ErrorWindow = ticks
+	}
+}
+
+// WithTickless switches the engine to tickless mode. Instead of ticking at a fixed rate, a tickless engine runs a tick
+// as soon as an Event is posted and sleeps while there is no work. If window is positive, the engine waits for window
+// after being woken up before ticking, so that Events posted in quick succession are handled in a single tick. Retries
+// and scheduled posts wake the engine up once they are due. The TPS is only used to convert retry delays measured in
+// ticks to wall time, at one tick period (1s/TPS) per tick.
+//
+// A tickless engine does not post PreTickEvent and PostTickEvent types unless WithTickEvents is also used.
+func WithTickless(window time.Duration) Option {
+	if window < 0 {
+		window = 0
+	}
+
+	return func(options *Options) {
+		options.Tickless = true
+		options.BatchWindow = window
+	}
+}
+
+// WithTickEvents makes a tickless engine post a PreTickEvent and a PostTickEvent around every tick, as an engine that
+// ticks at a fixed rate always does. Posting them does not wake the engine up.
+func WithTickEvents() Option {
+	return func(options *Options) {
+		options.TickEvents = true
+	}
+}
+
+// WithTickBudget limits how much work a single tick may do, by the number of Events it dispatches and/or the time it
+// spends dispatching them. Events left over once the budget is exhausted are carried over to the next tick in order of
+// priority. A zero field of budget means the corresponding limit does not apply.
+func WithTickBudget(budget bus.TickBudget) Option {
+	return func(options *Options) {
+		options.TickBudget = budget
+	}
+}
+
+// WithFixedTimestep switches the engine loop to fixed-timestep mode, in which every tick stands for exactly one tick
+// period (1s/TPS) of simulated time. When the engine loop falls behind, it runs up to maxCatchUp additional ticks in a
+// row to catch up instead of dropping them. Time it is still behind by after that is discarded, and the ticks it stood
+// for are counted as dropped (see Engine.DroppedTicks). Fixed-timestep mode has no effect on a tickless engine.
+func WithFixedTimestep(maxCatchUp int) Option {
+	if maxCatchUp < 0 {
+		maxCatchUp = 0
+	}
+
+	return func(options *Options) {
+		options.FixedTimestep = true
+		options.MaxCatchUp = maxCatchUp
+	}
+}
+
+// WithSchedule adds a named schedule that ticks at its own rate of tps ticks per second (see Schedule). Events posted
+// to a topic matched by one of topics are only handled on the ticks of the schedule.
+func WithSchedule(name string, tps int, topics ...string) Option {
+	if tps < 1 {
+		tps = 1
+	}
+
+	return func(options *Options) {
+		options.Schedules = append(options.Schedules, Schedule{
+			Name:   name,
+			TPS:    tps,
+			Topics: topics,
+		})
+	}
+}
+
+// WithPhase assigns every topic matched by one of topics to the named phase of every tick. Phases run in the order they
+// are first declared, after a default phase to which every other topic belongs, and every Event of a phase is handled
+// before the next phase begins. Events posted during a phase to a topic of a later phase are handled within the same
+// tick, while those posted to the same or an earlier phase are handled on the next tick.
+func WithPhase(name string, topics ...string) Option {
+	return func(options *Options) {
+		options.Phases = append(options.Phases, bus.Phase{
+			Name:     name,
+			Patterns: topics,
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package banji
+
+import (
+	"time"
+
+	"github.com/AndrewChon/banji/bus"
+)
+
+// DroppedTicks returns the number of ticks the engine loop has dropped because a previous tick overran the tick period.
+func (eng *Engine) DroppedTicks() uint64 {
+	return eng.dropped.Load()
+}
+
+// period returns the tick period of the engine loop.
+func (eng *Engine) period() time.Duration {
+	return time.Second / time.Duration(eng.options.TPS)
+}
+
+// overrun posts a TickOverrunEvent if the tick that began at start took longer than the tick period, counting the
+// ticks the ticker dropped in the meantime.
+func (eng *Engine) overrun(start time.Time, stats bus.TickStats) {
+	duration, period := time.Since(start), eng.period()
+	if duration <= period {
+		return
+	}
+
+	// The ticker keeps the first tick that should have begun while the tick was running, and drops the others.
+	dropped := uint64(duration/period) - 1
+	eng.dropped.Add(dropped)
+
+	eng.postOverrun(duration, stats, dropped)
+}
+
+// postOverrun posts a TickOverrunEvent for the last tick, which took duration and caused dropped ticks to be dropped.
+func (eng *Engine) postOverrun(duration time.Duration, stats bus.TickStats, dropped uint64) {
+	eng.Post(&TickOverrunEvent{
+		number:   eng.ticks.Load(),
+		duration: duration,
+		period:   eng.period(),
+		budget:   eng.options.TickBudget,
+		stats:    stats,
+		dropped:  dropped,
+	}, 0)
+}
@@ -0,0 +1,69 @@
+package banji
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotPaused is returned by Engine.Step when the engine is not paused.
+var ErrNotPaused = errors.New("engine is not paused")
+
+// Pause suspends the engine loop. Events can still be posted while the engine is paused, but they are not handled until
+// the engine is resumed or stepped through with Step. Pause waits for the ticks in progress, if any, to complete,
+// including those of named schedules, and no tick begins once it has returned.
+func (eng *Engine) Pause() {
+	eng.paused.Store(true)
+
+	eng.tickMu.Lock()
+	eng.tickMu.Unlock()
+
+	for _, s := range eng.schedules {
+		s.mu.Lock()
+		s.mu.Unlock()
+	}
+}
+
+// Resume resumes the engine loop after it has been suspended with Pause.
+func (eng *Engine) Resume() {
+	eng.paused.Store(false)
+	eng.wakeUp()
+}
+
+// Paused reports whether the engine loop is suspended.
+func (eng *Engine) Paused() bool {
+	return eng.paused.Load()
+}
+
+// Step synchronously runs n ticks of the engine loop, including their PreTickEvent and PostTickEvent, while the
+// engine is paused. Every tick of the engine loop is followed by a tick of every named schedule, with its own
+// PreTickEvent and PostTickEvent (see Schedule). It returns ErrNotPaused unless the engine is both active and paused.
+func (eng *Engine) Step(n int) error {
+	if !eng.active.Load() || !eng.paused.Load() {
+		return ErrNotPaused
+	}
+
+	for range n {
+		eng.step()
+	}
+
+	return nil
+}
+
+// step runs a single tick of the engine loop and of every schedule, whether or not the engine is paused.
+func (eng *Engine) step() {
+	eng.tickMu.Lock()
+	eng.runTick(time.Now(), tickTiming{})
+	eng.tickMu.Unlock()
+
+	for _, s := range eng.schedules {
+		s.mu.Lock()
+		eng.runScheduleTick(s, time.Now())
+		s.mu.Unlock()
+	}
+}
+
+// Pending returns the number of Events waiting to be handled on the next tick, per topic. Events waiting to be retried
+// and posts scheduled with PostAfter or PostAt are not included.
+func (eng *Engine) Pending() map[string]int {
+	return eng.bus.Pending()
+}
@@ -0,0 +1,132 @@
+package banji
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/AndrewChon/banji/bus"
+)
+
+var (
+	// ErrNoResponders is the result of a request posted to a topic that no Receiver is subscribed to.
+	ErrNoResponders = errors.New("no receivers are subscribed to the request topic")
+
+	// ErrNoEligibleResponders is the result of a request that every Receiver subscribed to its topic declined, either
+	// through its filter or because its circuit is open. It is wrapped along with bus.ErrFiltered or bus.ErrCircuitOpen.
+	ErrNoEligibleResponders = errors.New("no receiver subscribed to the request topic admitted the request")
+
+	// ErrNotAccepting is the result of a request made while the engine is not accepting events.
+	ErrNotAccepting = errors.New("engine is not accepting events")
+)
+
+// A Request is an Event that expects a reply from one of its receivers. Types implement Request by embedding
+// RequestEmbed in place of EventEmbed.
+type Request interface {
+	Event
+	Reply(value any) bool
+	Fail(err error) bool
+
+	bind(f *Future)
+}
+
+// RequestEmbed contains internal methods required to implement the Request interface.
+type RequestEmbed struct {
+	EventEmbed
+	future atomic.Pointer[Future]
+}
+
+// Reply resolves the request with value. Only the first reply or failure is delivered to the requester; Reply reports
+// whether this one was.
+func (r *RequestEmbed) Reply(value any) bool {
+	return r.resolve(value, nil)
+}
+
+// Fail resolves the request with err. Only the first reply or failure is delivered to the requester; Fail reports
+// whether this one was.
+func (r *RequestEmbed) Fail(err error) bool {
+	return r.resolve(nil, err)
+}
+
+func (r *RequestEmbed) resolve(value any, err error) bool {
+	f := r.future.Load()
+	if f == nil {
+		return false
+	}
+
+	return f.resolve(value, err)
+}
+
+func (r *RequestEmbed) bind(f *Future) {
+	r.future.Store(f)
+}
+
+// A Future is the eventual result of a Request.
+type Future struct {
+	done  chan struct{}
+	once  sync.Once
+	value any
+	err   error
+}
+
+func newFuture() *Future {
+	return &Future{
+		done: make(chan struct{}),
+	}
+}
+
+// Done returns a channel that is closed once the Future has been resolved.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the Future has been resolved and returns its result.
+func (f *Future) Wait() (any, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+func (f *Future) resolve(value any, err error) (resolved bool) {
+	f.once.Do(func() {
+		f.value = value
+		f.err = err
+		close(f.done)
+		resolved = true
+	})
+
+	return resolved
+}
+
+// Request posts a Request to the engine along with ctx (see PostContext) and returns a Future for its reply. The Future
+// is resolved with the first reply or failure from a Receiver, with the error of the first Receiver that fails to
+// handle the request once its retries are exhausted, with ErrNoResponders if no Receiver is subscribed to the request's
+// topic, with ErrNoEligibleResponders if every one of them declines it, with ErrNotAccepting if the engine is not
+// accepting events, or with the context's error if ctx is done first.
+func (eng *Engine) Request(ctx context.Context, req Request, priority uint8) *Future {
+	f := newFuture()
+	req.bind(f)
+
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				f.resolve(nil, ctx.Err())
+			case <-f.done:
+			}
+		}()
+	}
+
+	if !eng.post(ctx, req, priority) {
+		f.resolve(nil, ErrNotAccepting)
+	}
+
+	return f
+}
+
+// failRequest fails a Request that a Receiver failed to handle for good with the Receiver's error.
+func failRequest(failure bus.Failure) {
+	if req, ok := failure.Emittable.(Request); ok {
+		req.Fail(failure.Err)
+	}
+}
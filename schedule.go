@@ -0,0 +1,189 @@
+package banji
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AndrewChon/pqueue"
+)
+
+const (
+	postPending int32 = iota
+	postCanceled
+	postReleased
+)
+
+// A PendingPost is an Event that has been scheduled to be posted at a later time.
+type PendingPost struct {
+	event    Event
+	priority uint8
+	deadline time.Time
+	state    atomic.Int32
+
+	scheduler *scheduler
+}
+
+// Event returns the Event that is scheduled to be posted.
+func (p *PendingPost) Event() Event {
+	return p.event
+}
+
+// Deadline returns the earliest time at which the Event will be posted.
+func (p *PendingPost) Deadline() time.Time {
+	return p.deadline
+}
+
+// Cancel prevents the Event from being posted. It reports whether the post was still pending.
+func (p *PendingPost) Cancel() bool {
+	return p.scheduler.cancel(p)
+}
+
+// A scheduler holds pending posts in order of their deadlines. Canceled posts are left in place and counted, and are
+// discarded all at once whenever they make up more than half of the posts held, which keeps cancellation cheap even
+// when posts are canceled and scheduled again at a high rate.
+type scheduler struct {
+	mu      sync.Mutex
+	pending *pqueue.Pairing[int64, *PendingPost]
+
+	// canceled counts the canceled posts that are still held.
+	canceled int
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{
+		pending: pqueue.NewPairing[int64, *PendingPost](),
+	}
+}
+
+func (s *scheduler) schedule(p *PendingPost) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending.Push(p, p.deadline.UnixNano())
+}
+
+// release removes and returns every pending post whose deadline is at or before now and has not been canceled.
+func (s *scheduler) release(now time.Time) []*PendingPost {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*PendingPost
+	for s.pending.Size() > 0 && !s.pending.Peek().deadline.After(now) {
+		p, _ := s.pending.Pop()
+		if p.state.CompareAndSwap(postPending, postReleased) {
+			due = append(due, p)
+		} else {
+			s.canceled--
+		}
+	}
+
+	return due
+}
+
+// next returns the deadline of the earliest pending post, discarding any canceled posts ahead of it.
+func (s *scheduler) next() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.pending.Size() > 0 {
+		if p := s.pending.Peek(); p.state.Load() == postPending {
+			return p.deadline, true
+		}
+
+		s.pending.Pop()
+		s.canceled--
+	}
+
+	return time.Time{}, false
+}
+
+// cancel cancels a pending post and reports whether it was still pending.
+func (s *scheduler) cancel(p *PendingPost) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !p.state.CompareAndSwap(postPending, postCanceled) {
+		return false
+	}
+
+	s.canceled++
+	if s.canceled > s.pending.Size()/2 {
+		s.compact()
+	}
+
+	return true
+}
+
+// compact discards every canceled post.
+func (s *scheduler) compact() {
+	live := pqueue.NewPairing[int64, *PendingPost]()
+	for p, ok := s.pending.Pop(); ok; p, ok = s.pending.Pop() {
+		if p.state.Load() == postPending {
+			live.Push(p, p.deadline.UnixNano())
+		}
+	}
+
+	s.pending = live
+	s.canceled = 0
+}
+
+// discard removes and returns every pending post that has not been canceled. The returned posts can no longer be
+// canceled.
+func (s *scheduler) discard() []*PendingPost {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var discarded []*PendingPost
+	for p, ok := s.pending.Pop(); ok; p, ok = s.pending.Pop() {
+		if p.state.CompareAndSwap(postPending, postCanceled) {
+			discarded = append(discarded, p)
+		}
+	}
+
+	s.canceled = 0
+	return discarded
+}
+
+// size returns the number of pending posts that have not been canceled.
+func (s *scheduler) size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pending.Size() - s.canceled
+}
+
+// PostAfter schedules an Event to be posted on the first tick at or after d has elapsed. The returned PendingPost can
+// be used to cancel the post before it happens.
+func (eng *Engine) PostAfter(event Event, priority uint8, d time.Duration) *PendingPost {
+	return eng.PostAt(event, priority, time.Now().Add(d))
+}
+
+// PostAt schedules an Event to be posted on the first tick at or after t. The returned PendingPost can be used to
+// cancel the post before it happens. Pending posts are only released while the engine is running.
+func (eng *Engine) PostAt(event Event, priority uint8, t time.Time) *PendingPost {
+	p := &PendingPost{
+		event:     event,
+		priority:  priority,
+		deadline:  t,
+		scheduler: eng.scheduler,
+	}
+
+	eng.scheduler.schedule(p)
+	eng.rescheduled()
+
+	return p
+}
+
+// PendingPosts returns the number of posts scheduled with PostAfter or PostAt that have yet to be released or
+// canceled.
+func (eng *Engine) PendingPosts() int {
+	return eng.scheduler.size()
+}
+
+// releasePending posts every pending post that is due at now.
+func (eng *Engine) releasePending(now time.Time) {
+	for _, p := range eng.scheduler.release(now) {
+		eng.Post(p.event, p.priority)
+	}
+}
@@ -0,0 +1,183 @@
+package banji
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrAbandoned is the result of a request that was abandoned because the engine shut down before it was handled.
+var ErrAbandoned = errors.New("engine shut down before the event was handled")
+
+// A ShutdownPhase is a phase of shutting down the engine. Phases happen in the order they are declared.
+type ShutdownPhase int
+
+const (
+	// PreStopPhase happens before StopEvent is posted, while the engine still accepts new Events and keeps ticking.
+	// Additional ticks requested during PreStopPhase are run by the engine loop before StopEvent is posted.
+	PreStopPhase ShutdownPhase = iota
+
+	// StopPhase happens once StopEvent has been posted and the engine has stopped accepting new Events, right before
+	// the remaining Events are drained. Additional ticks requested during StopPhase are run while draining, even if no
+	// Events remain.
+	StopPhase
+
+	// PostStopPhase happens once the engine has drained, or has given up on draining, and is no longer active.
+	// Additional ticks requested during PostStopPhase are ignored.
+	PostStopPhase
+)
+
+func (p ShutdownPhase) String() string {
+	switch p {
+	case PreStopPhase:
+		return "pre-stop"
+	case StopPhase:
+		return "stop"
+	case PostStopPhase:
+		return "post-stop"
+	default:
+		return fmt.Sprintf("ShutdownPhase(%d)", int(p))
+	}
+}
+
+// A ComponentShutdown reports how a ShutdownComponent went through a ShutdownPhase.
+type ComponentShutdown struct {
+	Component Component
+	Phase     ShutdownPhase
+	Duration  time.Duration
+	Ticks     int
+	Err       error
+}
+
+// A ShutdownError is returned by Engine.Shutdown when the engine could not handle every Event before shutting down, or
+// when a ShutdownComponent failed to shut down.
+type ShutdownError struct {
+	// Abandoned holds the number of Events that were abandoned, per topic. Events waiting to be retried and posts
+	// scheduled with PostAfter or PostAt are counted along with queued Events.
+	Abandoned map[string]int
+
+	// Failures holds the reports of every ShutdownComponent that failed to go through a ShutdownPhase.
+	Failures []ComponentShutdown
+
+	// Err is the reason the engine stopped shutting down gracefully, such as context.DeadlineExceeded, or nil if it
+	// finished draining.
+	Err error
+}
+
+func (e *ShutdownError) Error() string {
+	var problems []string
+
+	if len(e.Abandoned) > 0 {
+		topics := make([]string, 0, len(e.Abandoned))
+		total := 0
+
+		for topic, count := range e.Abandoned {
+			topics = append(topics, fmt.Sprintf("%d on %q", count, topic))
+			total += count
+		}
+
+		slices.Sort(topics)
+		problems = append(problems, fmt.Sprintf("%d event(s) abandoned (%s)", total, strings.Join(topics, ", ")))
+	}
+
+	for _, f := range e.Failures {
+		problems = append(problems, fmt.Sprintf("%T failed during %s: %v", f.Component, f.Phase, f.Err))
+	}
+
+	msg := "engine shut down with " + strings.Join(problems, "; ")
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+func (e *ShutdownError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures)+1)
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+
+	for _, f := range e.Failures {
+		errs = append(errs, f.Err)
+	}
+
+	return errs
+}
+
+// ShutdownReports returns how every ShutdownComponent went through each ShutdownPhase during the last shutdown, in the
+// order they were shut down.
+func (eng *Engine) ShutdownReports() []ComponentShutdown {
+	eng.reportsMu.Lock()
+	defer eng.reportsMu.Unlock()
+
+	return slices.Clone(eng.reports)
+}
+
+// shutdownPhase calls the Shutdown method of every ShutdownComponent in reverse dependency order and returns the
+// largest number of additional ticks requested.
+func (eng *Engine) shutdownPhase(ctx context.Context, phase ShutdownPhase) int {
+	ticks := 0
+
+	for _, c := range eng.stopOrder {
+		start := time.Now()
+		requested, err := c.Shutdown(ctx, phase)
+
+		eng.reportsMu.Lock()
+		eng.reports = append(eng.reports, ComponentShutdown{
+			Component: c,
+			Phase:     phase,
+			Duration:  time.Since(start),
+			Ticks:     requested,
+			Err:       err,
+		})
+		eng.reportsMu.Unlock()
+
+		ticks = max(ticks, requested)
+	}
+
+	return ticks
+}
+
+// shutdownFailures returns the reports of the last shutdown that carry an error.
+func (eng *Engine) shutdownFailures() []ComponentShutdown {
+	eng.reportsMu.Lock()
+	defer eng.reportsMu.Unlock()
+
+	var failures []ComponentShutdown
+	for _, r := range eng.reports {
+		if r.Err != nil {
+			failures = append(failures, r)
+		}
+	}
+
+	return failures
+}
+
+// abandon discards every Event that has yet to be handled, as well as every pending post, and returns the number of
+// Events discarded per topic. Built-in broadcasts are discarded without being counted. Abandoned requests are failed
+// with ErrAbandoned.
+func (eng *Engine) abandon() map[string]int {
+	events := eng.bus.Discard()
+	for _, p := range eng.scheduler.discard() {
+		events = append(events, p.event)
+	}
+
+	abandoned := make(map[string]int)
+	for _, event := range events {
+		if _, ok := event.(broadcast); ok {
+			continue
+		}
+
+		if req, ok := event.(Request); ok {
+			req.Fail(fmt.Errorf("%w: %q", ErrAbandoned, req.Topic()))
+		}
+
+		abandoned[event.Topic()]++
+	}
+
+	return abandoned
+}
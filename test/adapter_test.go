@@ -0,0 +1,69 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+)
+
+const (
+	Timeout = 1 * time.Second
+)
+
+func TestFuncReceiver(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	started := make(chan *banji.StartEvent, 1)
+	banji.SubscribeFunc(eng, banji.StartTopic, func(e *banji.StartEvent) error {
+		started <- e
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	select {
+	case e := <-started:
+		validateEventImplementation(e, t)
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for *banji.StartEvent\n")
+	}
+}
+
+func TestFuncReceiverMismatch(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	mismatched := make(chan struct{}, 1)
+	banji.SubscribeFunc(eng, banji.StartTopic, func(e *banji.StopEvent) error {
+		mismatched <- struct{}{}
+		return nil
+	})
+
+	errs := make(chan error, 1)
+	banji.SubscribeFunc(eng, banji.ErrorTopic, func(e *banji.ErrorEvent) error {
+		errs <- e.Error()
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, banji.ErrUnexpectedEvent) {
+			t.Fatalf("Expected banji.ErrUnexpectedEvent, received %v\n", err)
+		}
+	case <-mismatched:
+		t.Fatalf("Handler was called with a mismatched event\n")
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for *banji.ErrorEvent\n")
+	}
+}
@@ -0,0 +1,191 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+	"github.com/AndrewChon/banji/bus"
+)
+
+const (
+	StuckTopic     = "test.stuck"
+	HandlerTimeout = 50 * time.Millisecond
+)
+
+type StuckEvent struct {
+	banji.EventEmbed
+}
+
+func (e *StuckEvent) Topic() string {
+	return StuckTopic
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithHandlerTimeout(HandlerTimeout),
+	)
+
+	canceled := make(chan error, 1)
+	eng.Subscribe(banji.OnContext(StuckTopic, func(ctx context.Context, e *StuckEvent) error {
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return nil
+	}))
+
+	errs := make(chan error, 1)
+	banji.SubscribeFunc(eng, banji.ErrorTopic, func(e *banji.ErrorEvent) error {
+		errs <- e.Error()
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	eng.Post(new(StuckEvent), 0)
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, bus.ErrHandlerTimeout) {
+			t.Fatalf("Expected bus.ErrHandlerTimeout, received %v\n", err)
+		}
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for *banji.ErrorEvent\n")
+	}
+
+	select {
+	case err := <-canceled:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected context.DeadlineExceeded, received %v\n", err)
+		}
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for the handler's context to be canceled\n")
+	}
+}
+
+type (
+	traceKey struct{}
+	spanKey  struct{}
+)
+
+func TestContextPropagation(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	traces := make(chan any, 2)
+	eng.Subscribe(banji.OnContext(FailingTopic, func(ctx context.Context, e *FailingEvent) error {
+		traces <- ctx.Value(traceKey{})
+		return errFailing
+	}))
+
+	banji.SubscribeFunc(eng, banji.ErrorTopic, func(e *banji.ErrorEvent) error {
+		traces <- e.Context().Value(traceKey{})
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace")
+	eng.PostContext(ctx, new(FailingEvent), 0)
+
+	for range 2 {
+		select {
+		case trace := <-traces:
+			if trace != "trace" {
+				t.Fatalf("Expected trace %q, received %v\n", "trace", trace)
+			}
+		case <-time.After(Timeout):
+			t.Fatalf("Timed out waiting for the context to propagate\n")
+		}
+	}
+}
+
+func TestHandlerContextPropagation(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	eng.Subscribe(banji.OnContext(StuckTopic, func(ctx context.Context, e *StuckEvent) error {
+		ctx, cancel := context.WithTimeout(context.WithValue(ctx, spanKey{}, "span"), time.Hour)
+		defer cancel()
+
+		eng.PostContext(ctx, new(FailingEvent), 0)
+		return nil
+	}))
+
+	children := make(chan context.Context, 1)
+	banji.SubscribeFunc(eng, FailingTopic, func(e *FailingEvent) error {
+		children <- e.Context()
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace")
+	eng.PostContext(ctx, new(StuckEvent), 0)
+
+	select {
+	case child := <-children:
+		if err := child.Err(); err != nil {
+			t.Fatalf("Expected the context to outlive the handler, received %v\n", err)
+		}
+
+		if child.Value(traceKey{}) != "trace" || child.Value(spanKey{}) != "span" {
+			t.Fatalf("Expected the trace and span values, received %v and %v\n",
+				child.Value(traceKey{}), child.Value(spanKey{}))
+		}
+
+		if _, ok := child.Deadline(); !ok {
+			t.Fatalf("Expected the deadline set by the handler to be kept\n")
+		}
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for the context to propagate\n")
+	}
+}
+
+func TestCanceledContext(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	handled := make(chan struct{}, 1)
+	banji.SubscribeFunc(eng, FailingTopic, func(e *FailingEvent) error {
+		handled <- struct{}{}
+		return nil
+	})
+
+	deadLetters := make(chan error, 1)
+	banji.SubscribeFunc(eng, banji.DeadLetterTopic, func(e *banji.DeadLetterEvent) error {
+		deadLetters <- e.Reason()
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	eng.PostContext(ctx, new(FailingEvent), 0)
+
+	select {
+	case reason := <-deadLetters:
+		if !errors.Is(reason, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, received %v\n", reason)
+		}
+	case <-handled:
+		t.Fatalf("Event with a canceled context was handled\n")
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for *banji.DeadLetterEvent\n")
+	}
+}
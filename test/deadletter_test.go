@@ -0,0 +1,125 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+	"github.com/AndrewChon/banji/bus"
+)
+
+const (
+	MisspelledTopic = "test.misspeled"
+)
+
+type MisspelledEvent struct {
+	banji.EventEmbed
+}
+
+func (e *MisspelledEvent) Topic() string {
+	return MisspelledTopic
+}
+
+func TestDeadLetter(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	deadLetters := make(chan *banji.DeadLetterEvent, 1)
+	banji.SubscribeFunc(eng, banji.DeadLetterTopic, func(e *banji.DeadLetterEvent) error {
+		deadLetters <- e
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	event := new(MisspelledEvent)
+	eng.Post(event, 0)
+
+	select {
+	case e := <-deadLetters:
+		if e.Event() != event {
+			t.Fatalf("Expected dead letter for %v, received %v\n", event, e.Event())
+		}
+
+		if !errors.Is(e.Reason(), bus.ErrNoSubscribers) {
+			t.Fatalf("Expected bus.ErrNoSubscribers, received %v\n", e.Reason())
+		}
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for *banji.DeadLetterEvent\n")
+	}
+
+	if count := eng.DeadLetterCount(MisspelledTopic); count != 1 {
+		t.Fatalf("Expected 1 dead letter for %q, counted %d\n", MisspelledTopic, count)
+	}
+
+	// Built-in broadcasts must never be dead-lettered, even though nothing is subscribed to them.
+	for _, topic := range []string{banji.StartTopic, banji.PreTickTopic, banji.PostTickTopic} {
+		if count := eng.DeadLetterCount(topic); count != 0 {
+			t.Fatalf("Expected no dead letters for %q, counted %d\n", topic, count)
+		}
+	}
+}
+
+func TestDeadLetterOpenCircuit(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithCircuitBreaker(bus.CircuitBreaker{
+			Window:    1,
+			Threshold: 1,
+			Cooldown:  time.Hour,
+		}),
+	)
+
+	errFailing := errors.New("failing receiver")
+	banji.SubscribeFunc(eng, MisspelledTopic, func(_ *MisspelledEvent) error {
+		return errFailing
+	})
+
+	failed := make(chan struct{}, 1)
+	banji.SubscribeFunc(eng, banji.ErrorTopic, func(_ *banji.ErrorEvent) error {
+		failed <- struct{}{}
+		return nil
+	})
+
+	deadLetters := make(chan *banji.DeadLetterEvent, 1)
+	banji.SubscribeFunc(eng, banji.DeadLetterTopic, func(e *banji.DeadLetterEvent) error {
+		if _, ok := e.Event().(*MisspelledEvent); ok {
+			deadLetters <- e
+		}
+
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	// The first Event opens the circuit, so that the second is rejected.
+	eng.Post(new(MisspelledEvent), 0)
+
+	select {
+	case <-failed:
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for *banji.ErrorEvent\n")
+	}
+
+	rejected := new(MisspelledEvent)
+	eng.Post(rejected, 0)
+
+	select {
+	case e := <-deadLetters:
+		if e.Event() != rejected {
+			t.Fatalf("Expected dead letter for %v, received %v\n", rejected, e.Event())
+		}
+
+		if !errors.Is(e.Reason(), bus.ErrCircuitOpen) {
+			t.Fatalf("Expected bus.ErrCircuitOpen, received %v\n", e.Reason())
+		}
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for *banji.DeadLetterEvent\n")
+	}
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+)
+
+const (
+	FailingTopic = "test.failing"
+	ErrorWindow  = 16
+)
+
+var (
+	errFailing = errors.New("failing receiver")
+)
+
+type FailingEvent struct {
+	banji.EventEmbed
+}
+
+func (e *FailingEvent) Topic() string {
+	return FailingTopic
+}
+
+func TestErrorEventContext(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	failing := banji.SubscribeFunc(eng, FailingTopic, func(e *FailingEvent) error {
+		return errFailing
+	})
+
+	errs := make(chan *banji.ErrorEvent, 1)
+	banji.SubscribeFunc(eng, banji.ErrorTopic, func(e *banji.ErrorEvent) error {
+		errs <- e
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	event := new(FailingEvent)
+	eng.Post(event, 0)
+
+	select {
+	case e := <-errs:
+		if !errors.Is(e.Error(), errFailing) {
+			t.Fatalf("Expected errFailing, received %v\n", e.Error())
+		}
+
+		if e.Source() != event || e.SourceTopic() != FailingTopic {
+			t.Fatalf("Expected source %v on %q, received %v on %q\n", event, FailingTopic, e.Source(), e.SourceTopic())
+		}
+
+		if e.Receiver() != failing || e.ReceiverID() != failing.ID() {
+			t.Fatalf("Expected receiver %v, received %v\n", failing.ID(), e.ReceiverID())
+		}
+
+		if !strings.HasPrefix(e.ReceiverType(), "*banji.FuncReceiver[") {
+			t.Fatalf("Unexpected receiver type %q\n", e.ReceiverType())
+		}
+
+		if e.Attempt() != 1 {
+			t.Fatalf("Expected attempt 1, received %d\n", e.Attempt())
+		}
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for *banji.ErrorEvent\n")
+	}
+}
+
+func TestErrorLoopProtection(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithMaxErrorDepth(2),
+		banji.WithErrorWindow(ErrorWindow),
+	)
+
+	banji.SubscribeFunc(eng, FailingTopic, func(e *FailingEvent) error {
+		return errFailing
+	})
+
+	var depths sync.Map
+	banji.SubscribeFunc(eng, banji.ErrorTopic, func(e *banji.ErrorEvent) error {
+		depths.Store(e.Depth(), true)
+		return errFailing
+	})
+
+	summaries := make(chan *banji.ErrorSummaryEvent, 1)
+	banji.SubscribeFunc(eng, banji.ErrorSummaryTopic, func(e *banji.ErrorSummaryEvent) error {
+		summaries <- e
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	const repeats = 5
+	for range repeats {
+		eng.Post(new(FailingEvent), 0)
+	}
+
+	// Every repeat after the first is suppressed, as is the ErrorEvent nested beyond the maximum depth. The cascade may
+	// straddle two windows, so the summaries are totalled.
+	suppressed := 0
+	for suppressed < repeats {
+		select {
+		case e := <-summaries:
+			suppressed += e.Suppressed()
+		case <-time.After(Timeout):
+			t.Fatalf("Expected %d suppressed errors, received %d\n", repeats, suppressed)
+		}
+	}
+
+	if suppressed != repeats {
+		t.Fatalf("Expected %d suppressed errors, received %d\n", repeats, suppressed)
+	}
+
+	for _, depth := range []int{1, 2} {
+		if _, ok := depths.Load(depth); !ok {
+			t.Fatalf("Expected an ErrorEvent of depth %d\n", depth)
+		}
+	}
+
+	if _, ok := depths.Load(3); ok {
+		t.Fatalf("Expected no ErrorEvent of depth 3\n")
+	}
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+)
+
+const (
+	// Stall is several times longer than the tick period at TPS.
+	Stall = 50 * time.Millisecond
+)
+
+func TestTickOverrun(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	slow := banji.SubscribeFunc(eng, SlowTopic, func(e *SlowEvent) error {
+		time.Sleep(Stall)
+		return nil
+	})
+
+	overruns := make(chan *banji.TickOverrunEvent, 1)
+	banji.SubscribeFunc(eng, banji.TickOverrunTopic, func(e *banji.TickOverrunEvent) error {
+		if e.Slowest().SubscriberID == slow.ID() {
+			overruns <- e
+		}
+
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	eng.Post(new(SlowEvent), 0)
+
+	select {
+	case e := <-overruns:
+		if e.Duration() < Stall || e.Duration() <= e.Period() {
+			t.Fatalf("Expected a duration of at least %v, received %v\n", Stall, e.Duration())
+		}
+
+		if e.Slowest().Topic != SlowTopic || e.Slowest().Duration < Stall {
+			t.Fatalf("Unexpected slowest handler %+v\n", e.Slowest())
+		}
+
+		if e.Processed() < 1 {
+			t.Fatalf("Expected at least one processed event, received %d\n", e.Processed())
+		}
+
+		if e.Dropped() == 0 || eng.DroppedTicks() < e.Dropped() {
+			t.Fatalf("Expected dropped ticks to be counted, received %d of %d\n", e.Dropped(), eng.DroppedTicks())
+		}
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for *banji.TickOverrunEvent\n")
+	}
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+)
+
+const (
+	SteppedTopic = "test.stepped"
+)
+
+type SteppedEvent struct {
+	banji.EventEmbed
+}
+
+func (e *SteppedEvent) Topic() string {
+	return SteppedTopic
+}
+
+func TestPauseAndStep(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	var handled, preTicks atomic.Int64
+	banji.SubscribeFunc(eng, SteppedTopic, func(e *SteppedEvent) error {
+		handled.Add(1)
+		return nil
+	})
+
+	banji.SubscribeFunc(eng, banji.PreTickTopic, func(e *banji.PreTickEvent) error {
+		preTicks.Add(1)
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	if err := eng.Step(1); !errors.Is(err, banji.ErrNotPaused) {
+		t.Fatalf("Expected ErrNotPaused, received %v\n", err)
+	}
+
+	eng.Pause()
+
+	const posted = 3
+	for range posted {
+		eng.Post(new(SteppedEvent), 0)
+	}
+
+	// No tick should run while the engine is paused.
+	time.Sleep(100 * time.Millisecond)
+
+	if handled.Load() != 0 {
+		t.Fatalf("Expected no events to be handled while paused, received %d\n", handled.Load())
+	}
+
+	if pending := eng.Pending()[SteppedTopic]; pending != posted {
+		t.Fatalf("Expected %d pending events, received %d\n", posted, pending)
+	}
+
+	ticks, before := eng.Ticks(), preTicks.Load()
+	if err := eng.Step(2); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+
+	if handled.Load() != posted {
+		t.Fatalf("Expected %d events to be handled after stepping, received %d\n", posted, handled.Load())
+	}
+
+	if eng.Ticks() != ticks+2 {
+		t.Fatalf("Expected %d ticks, received %d\n", ticks+2, eng.Ticks())
+	}
+
+	if stepped := preTicks.Load() - before; stepped != 2 {
+		t.Fatalf("Expected 2 PreTickEvent types to be handled, received %d\n", stepped)
+	}
+
+	// The PostTickEvent of the last step is handled on the tick after it.
+	if pending := eng.Pending()[banji.PostTickTopic]; pending != 1 {
+		t.Fatalf("Expected 1 pending PostTickEvent, received %d\n", pending)
+	}
+
+	eng.Resume()
+	eng.Post(new(SteppedEvent), 0)
+
+	deadline := time.After(Timeout)
+	for handled.Load() != posted+1 {
+		select {
+		case <-deadline:
+			t.Fatalf("Timed out waiting for the engine to resume\n")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestPauseSchedules(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithSchedule(MetricsSchedule, TPS, MetricsTopic),
+	)
+
+	var preTicks atomic.Int64
+	banji.SubscribeFunc(eng, banji.PreTickTopic+"."+MetricsSchedule, func(e *banji.PreTickEvent) error {
+		preTicks.Add(1)
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	deadline := time.Now().Add(Timeout)
+	for preTicks.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for the schedule to tick\n")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	// Once Pause has returned, the schedule must not tick until the engine is resumed.
+	eng.Pause()
+	paused := preTicks.Load()
+	time.Sleep(100 * time.Millisecond)
+
+	if preTicks.Load() != paused {
+		t.Fatalf("Expected the schedule to stay paused, received %d ticks\n", preTicks.Load()-paused)
+	}
+
+	eng.Resume()
+	time.Sleep(100 * time.Millisecond)
+
+	if preTicks.Load() == paused {
+		t.Fatalf("Expected the schedule to tick once resumed\n")
+	}
+}
+
+func TestStepSchedules(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithSchedule(MetricsSchedule, 1, MetricsTopic),
+	)
+
+	var preTicks atomic.Int64
+	banji.SubscribeFunc(eng, banji.PreTickTopic+"."+MetricsSchedule, func(e *banji.PreTickEvent) error {
+		preTicks.Add(1)
+		return nil
+	})
+
+	var handled atomic.Int64
+	banji.SubscribeFunc(eng, MetricsTopic, func(e *MetricsEvent) error {
+		handled.Add(1)
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	eng.Pause()
+	ticked := preTicks.Load()
+	eng.Post(new(MetricsEvent), 0)
+
+	// Stepping the engine must also tick the schedule, which is otherwise paused along with it.
+	if err := eng.Step(1); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+
+	if handled.Load() != 1 {
+		t.Fatalf("Expected the event of the schedule to be handled by Step, handled %d\n", handled.Load())
+	}
+
+	if preTicks.Load() != ticked+1 {
+		t.Fatalf("Expected a PreTickEvent of %q per step, received %d\n", MetricsSchedule, preTicks.Load()-ticked)
+	}
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/AndrewChon/banji"
+)
+
+const (
+	UpdateTopic = "test.update"
+	RenderTopic = "test.render"
+)
+
+type UpdateEvent struct {
+	banji.EventEmbed
+}
+
+func (e *UpdateEvent) Topic() string {
+	return UpdateTopic
+}
+
+type RenderEvent struct {
+	banji.EventEmbed
+}
+
+func (e *RenderEvent) Topic() string {
+	return RenderTopic
+}
+
+func TestPhases(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithPhase("update", UpdateTopic),
+		banji.WithPhase("render", RenderTopic),
+	)
+
+	var updated, rendered atomic.Int64
+	eng.Subscribe(banji.OnContext(UpdateTopic, func(ctx context.Context, e *UpdateEvent) error {
+		updated.Add(1)
+		eng.PostContext(ctx, new(RenderEvent), 0)
+		return nil
+	}))
+
+	banji.SubscribeFunc(eng, RenderTopic, func(e *RenderEvent) error {
+		// Every update of the tick must be complete before rendering begins.
+		if updated.Load() != 1 {
+			t.Errorf("Expected the update phase to be complete, received %d updates\n", updated.Load())
+		}
+
+		rendered.Add(1)
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	eng.Pause()
+	eng.Post(new(UpdateEvent), 0)
+
+	if err := eng.Step(1); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+
+	// The RenderEvent posted during the update phase must be handled within the same tick.
+	if updated.Load() != 1 || rendered.Load() != 1 {
+		t.Fatalf("Expected 1 update and 1 render, received %d and %d\n", updated.Load(), rendered.Load())
+	}
+}
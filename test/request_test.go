@@ -0,0 +1,154 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+)
+
+const (
+	PingTopic = "test.ping"
+)
+
+type PingRequest struct {
+	banji.RequestEmbed
+	topic string
+}
+
+func (r *PingRequest) Topic() string {
+	return r.topic
+}
+
+func TestRequestReply(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	banji.SubscribeFunc(eng, PingTopic, func(r *PingRequest) error {
+		r.Reply("pong")
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	reply, err := eng.Request(ctx, &PingRequest{topic: PingTopic}, 0).Wait()
+	if err != nil {
+		t.Fatalf("Request failed: %v\n", err)
+	}
+
+	if reply != "pong" {
+		t.Fatalf("Expected reply %q, received %v\n", "pong", reply)
+	}
+}
+
+func TestRequestNoResponders(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	eng.Start()
+	defer eng.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	_, err := eng.Request(ctx, &PingRequest{topic: PingTopic}, 0).Wait()
+	if !errors.Is(err, banji.ErrNoResponders) {
+		t.Fatalf("Expected banji.ErrNoResponders, received %v\n", err)
+	}
+}
+
+func TestRequestFailingResponder(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	banji.SubscribeFunc(eng, PingTopic, func(r *PingRequest) error {
+		return errFailing
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	f := eng.Request(context.Background(), &PingRequest{topic: PingTopic}, 0)
+
+	select {
+	case <-f.Done():
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for the request to fail\n")
+	}
+
+	if _, err := f.Wait(); !errors.Is(err, errFailing) {
+		t.Fatalf("Expected the responder's error, received %v\n", err)
+	}
+}
+
+func TestRequestNoEligibleResponders(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	reject := func(event banji.Event) bool { return false }
+	banji.SubscribeFunc(eng, PingTopic, func(r *PingRequest) error {
+		r.Reply("pong")
+		return nil
+	}, reject)
+
+	eng.Start()
+	defer eng.Stop()
+
+	f := eng.Request(context.Background(), &PingRequest{topic: PingTopic}, 0)
+
+	select {
+	case <-f.Done():
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for the request to fail\n")
+	}
+
+	if _, err := f.Wait(); !errors.Is(err, banji.ErrNoEligibleResponders) {
+		t.Fatalf("Expected banji.ErrNoEligibleResponders, received %v\n", err)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	// This receiver never replies.
+	banji.SubscribeFunc(eng, PingTopic, func(r *PingRequest) error {
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := eng.Request(ctx, &PingRequest{topic: PingTopic}, 0).Wait()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, received %v\n", err)
+	}
+}
+
+func TestRequestNotAccepting(t *testing.T) {
+	eng := banji.New()
+
+	_, err := eng.Request(context.Background(), &PingRequest{topic: PingTopic}, 0).Wait()
+	if !errors.Is(err, banji.ErrNotAccepting) {
+		t.Fatalf("Expected banji.ErrNotAccepting, received %v\n", err)
+	}
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+)
+
+const (
+	ScheduledTopic = "test.scheduled"
+	Delay          = 100 * time.Millisecond
+)
+
+type ScheduledEvent struct {
+	banji.EventEmbed
+}
+
+func (e *ScheduledEvent) Topic() string {
+	return ScheduledTopic
+}
+
+func TestPostAfter(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	received := make(chan time.Time, 1)
+	banji.SubscribeFunc(eng, ScheduledTopic, func(e *ScheduledEvent) error {
+		received <- time.Now()
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	p := eng.PostAfter(new(ScheduledEvent), 0, Delay)
+
+	select {
+	case at := <-received:
+		if at.Before(p.Deadline()) {
+			t.Fatalf("Event was handled %v before its deadline\n", p.Deadline().Sub(at))
+		}
+	case <-time.After(Delay + Timeout):
+		t.Fatalf("Timed out waiting for scheduled event\n")
+	}
+}
+
+func TestPostAfterCancel(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	received := make(chan struct{}, 1)
+	banji.SubscribeFunc(eng, ScheduledTopic, func(e *ScheduledEvent) error {
+		received <- struct{}{}
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	p := eng.PostAfter(new(ScheduledEvent), 0, Delay)
+	if !p.Cancel() {
+		t.Fatalf("Expected pending post to be canceled\n")
+	}
+
+	select {
+	case <-received:
+		t.Fatalf("Canceled event was handled\n")
+	case <-time.After(2 * Delay):
+	}
+
+	if eng.PendingPosts() != 0 {
+		t.Fatalf("Expected no pending posts, found %d\n", eng.PendingPosts())
+	}
+}
+
+func TestPostAfterRearm(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	// Re-arming a timeout cancels the previous post, which must stop counting right away.
+	p := eng.PostAfter(new(ScheduledEvent), 0, time.Hour)
+	for range 1000 {
+		p.Cancel()
+		p = eng.PostAfter(new(ScheduledEvent), 0, time.Hour)
+	}
+
+	if eng.PendingPosts() != 1 {
+		t.Fatalf("Expected 1 pending post, found %d\n", eng.PendingPosts())
+	}
+
+	if !p.Cancel() || p.Cancel() {
+		t.Fatalf("Expected the last pending post to be canceled exactly once\n")
+	}
+
+	if eng.PendingPosts() != 0 {
+		t.Fatalf("Expected no pending posts, found %d\n", eng.PendingPosts())
+	}
+}
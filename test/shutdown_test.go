@@ -0,0 +1,336 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+	"github.com/AndrewChon/banji/bus"
+)
+
+const (
+	SlowTopic       = "test.slow"
+	ShutdownTimeout = 100 * time.Millisecond
+)
+
+type SlowEvent struct {
+	banji.EventEmbed
+}
+
+func (e *SlowEvent) Topic() string {
+	return SlowTopic
+}
+
+func TestShutdown(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	handled := make(chan struct{}, 1)
+	banji.SubscribeFunc(eng, SlowTopic, func(e *SlowEvent) error {
+		handled <- struct{}{}
+		return nil
+	})
+
+	eng.Start()
+	eng.Post(new(SlowEvent), 0)
+
+	if err := eng.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected a clean shutdown, received %v\n", err)
+	}
+
+	select {
+	case <-handled:
+	default:
+		t.Fatalf("Expected the queued event to be handled before shutting down\n")
+	}
+
+	if eng.Active() {
+		t.Fatalf("Expected the engine to be inactive\n")
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	// The first event blocks its tick until the engine gives up on draining, so that the others are abandoned.
+	started := make(chan struct{}, 1)
+	eng.Subscribe(banji.OnContext(SlowTopic, func(ctx context.Context, e *SlowEvent) error {
+		select {
+		case started <- struct{}{}:
+			<-ctx.Done()
+			return ctx.Err()
+		default:
+			return nil
+		}
+	}))
+
+	eng.Start()
+	eng.Post(new(SlowEvent), 0)
+
+	select {
+	case <-started:
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for the slow event to be handled\n")
+	}
+
+	const queued = 3
+	for range queued {
+		eng.Post(new(SlowEvent), 0)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+
+	begin := time.Now()
+	err := eng.Shutdown(ctx)
+
+	if elapsed := time.Since(begin); elapsed > ShutdownTimeout+Timeout {
+		t.Fatalf("Shutdown took %v despite a deadline of %v\n", elapsed, ShutdownTimeout)
+	}
+
+	var shutdownErr *banji.ShutdownError
+	if !errors.As(err, &shutdownErr) {
+		t.Fatalf("Expected a *banji.ShutdownError, received %v\n", err)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the error to wrap context.DeadlineExceeded, received %v\n", err)
+	}
+
+	if shutdownErr.Abandoned[SlowTopic] != queued {
+		t.Fatalf("Expected %d abandoned events on %q, received %v\n", queued, SlowTopic, shutdownErr.Abandoned)
+	}
+}
+
+func TestShutdownAbandonsPending(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithRetryPolicy(FailingTopic, bus.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     bus.FixedBackoff(time.Hour),
+		}),
+	)
+
+	failed := make(chan struct{}, 1)
+	banji.SubscribeFunc(eng, FailingTopic, func(e *FailingEvent) error {
+		failed <- struct{}{}
+		return errFailing
+	})
+
+	eng.Start()
+	eng.PostAfter(new(ScheduledEvent), 0, time.Hour)
+	eng.Post(new(FailingEvent), 0)
+
+	select {
+	case <-failed:
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for the failing event to be handled\n")
+	}
+
+	var shutdownErr *banji.ShutdownError
+	if err := eng.Shutdown(context.Background()); !errors.As(err, &shutdownErr) {
+		t.Fatalf("Expected a *banji.ShutdownError, received %v\n", err)
+	}
+
+	if shutdownErr.Err != nil {
+		t.Fatalf("Expected the engine to finish draining, received %v\n", shutdownErr.Err)
+	}
+
+	expected := map[string]int{
+		FailingTopic:   1,
+		ScheduledTopic: 1,
+	}
+
+	for topic, count := range expected {
+		if shutdownErr.Abandoned[topic] != count {
+			t.Fatalf("Expected %d abandoned events on %q, received %v\n", count, topic, shutdownErr.Abandoned)
+		}
+	}
+
+	if eng.PendingPosts() != 0 {
+		t.Fatalf("Expected no pending posts, received %d\n", eng.PendingPosts())
+	}
+}
+
+// shutdownComponent records every ShutdownPhase it goes through in a shared log.
+type shutdownComponent struct {
+	name      string
+	eng       **banji.Engine
+	deps      []banji.Component
+	ticks     int
+	err       error
+	log       *[]string
+	tickedAt  uint64
+	requested bool
+}
+
+func (c *shutdownComponent) Bootstrap() ([]banji.Receiver, error) {
+	return nil, nil
+}
+
+func (c *shutdownComponent) DependsOn() []banji.Component {
+	return c.deps
+}
+
+func (c *shutdownComponent) Shutdown(_ context.Context, phase banji.ShutdownPhase) (int, error) {
+	*c.log = append(*c.log, c.name+" "+phase.String())
+
+	switch phase {
+	case banji.PreStopPhase:
+		c.tickedAt = (*c.eng).Ticks()
+		return c.ticks, nil
+	case banji.StopPhase:
+		// Every tick requested during PreStopPhase must have run by now.
+		c.requested = (*c.eng).Ticks() >= c.tickedAt+uint64(c.ticks)
+		return 0, nil
+	default:
+		return 0, c.err
+	}
+}
+
+func TestShutdownPhases(t *testing.T) {
+	var eng *banji.Engine
+	var log []string
+
+	errStorage := errors.New("storage failed to close")
+	storage := &shutdownComponent{
+		name: "storage",
+		eng:  &eng,
+		err:  errStorage,
+		log:  &log,
+	}
+
+	service := &shutdownComponent{
+		name:  "service",
+		eng:   &eng,
+		deps:  []banji.Component{storage},
+		ticks: 2,
+		log:   &log,
+	}
+
+	// The storage is registered first, but must be shut down last since the service depends on it.
+	eng = banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithComponents(storage, service),
+	)
+
+	eng.Start()
+	err := eng.Shutdown(context.Background())
+
+	if !errors.Is(err, errStorage) {
+		t.Fatalf("Expected the error to wrap errStorage, received %v\n", err)
+	}
+
+	expected := []string{
+		"service pre-stop", "storage pre-stop",
+		"service stop", "storage stop",
+		"service post-stop", "storage post-stop",
+	}
+
+	if !slices.Equal(log, expected) {
+		t.Fatalf("Expected %v, received %v\n", expected, log)
+	}
+
+	if !service.requested {
+		t.Fatalf("Expected the ticks requested by the service to run before the stop phase\n")
+	}
+
+	reports := eng.ShutdownReports()
+	if len(reports) != len(expected) {
+		t.Fatalf("Expected %d reports, received %d\n", len(expected), len(reports))
+	}
+
+	last := reports[len(reports)-1]
+	if last.Component != storage || last.Phase != banji.PostStopPhase || !errors.Is(last.Err, errStorage) {
+		t.Fatalf("Unexpected report %+v\n", last)
+	}
+}
+
+func TestShutdownIndependentComponents(t *testing.T) {
+	var eng *banji.Engine
+	var log []string
+
+	first := &shutdownComponent{name: "first", eng: &eng, log: &log}
+	second := &shutdownComponent{name: "second", eng: &eng, log: &log}
+
+	// Components that do not depend on each other are shut down in the reverse of the order they were registered in.
+	eng = banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithComponents(first, second),
+	)
+
+	eng.Start()
+	if err := eng.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+
+	expected := []string{
+		"second pre-stop", "first pre-stop",
+		"second stop", "first stop",
+		"second post-stop", "first post-stop",
+	}
+
+	if !slices.Equal(log, expected) {
+		t.Fatalf("Expected %v, received %v\n", expected, log)
+	}
+}
+
+func TestShutdownDependencyCycle(t *testing.T) {
+	var log []string
+
+	a := &shutdownComponent{name: "a", log: &log}
+	b := &shutdownComponent{name: "b", log: &log, deps: []banji.Component{a}}
+	a.deps = []banji.Component{b}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Expected New to panic on a dependency cycle\n")
+		}
+	}()
+
+	banji.New(banji.WithComponents(a, b))
+}
+
+// namedComponent is a Component whose type cannot be hashed.
+type namedComponent struct {
+	names []string
+}
+
+func (c namedComponent) Bootstrap() ([]banji.Receiver, error) {
+	return nil, nil
+}
+
+func TestShutdownUnhashableComponents(t *testing.T) {
+	var eng *banji.Engine
+	var log []string
+	storage := &shutdownComponent{name: "storage", eng: &eng, log: &log}
+
+	// Neither a plain Component nor one mixed with ShutdownComponent types may need to be hashed.
+	banji.New(banji.WithComponents(namedComponent{names: []string{"a"}}))
+
+	eng = banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithComponents(namedComponent{names: []string{"a"}}, storage),
+	)
+
+	eng.Start()
+	if err := eng.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+
+	if len(log) != 3 {
+		t.Fatalf("Expected the storage to go through 3 phases, received %v\n", log)
+	}
+}
@@ -0,0 +1,188 @@
+package test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+	"github.com/AndrewChon/banji/bus"
+)
+
+const (
+	// TicklessTPS is low enough that an Event handled quickly cannot have waited for a regular tick.
+	TicklessTPS = 1
+	Idle        = 100 * time.Millisecond
+)
+
+func TestTickless(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TicklessTPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithTickless(0),
+	)
+
+	var preTicks atomic.Int64
+	banji.SubscribeFunc(eng, banji.PreTickTopic, func(e *banji.PreTickEvent) error {
+		preTicks.Add(1)
+		return nil
+	})
+
+	handled := make(chan struct{}, 1)
+	banji.SubscribeFunc(eng, SteppedTopic, func(e *SteppedEvent) error {
+		handled <- struct{}{}
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	begin := time.Now()
+	eng.Post(new(SteppedEvent), 0)
+
+	select {
+	case <-handled:
+	case <-time.After(Timeout / 2):
+		t.Fatalf("Expected the event to be handled without waiting for a tick\n")
+	}
+
+	t.Logf("Handled in %v\n", time.Since(begin))
+
+	// The engine must sleep while there is no work, once it is done with the tick in progress.
+	time.Sleep(Idle)
+	ticks := eng.Ticks()
+	time.Sleep(Idle)
+
+	if eng.Ticks() != ticks {
+		t.Fatalf("Expected no ticks while idle, received %d\n", eng.Ticks()-ticks)
+	}
+
+	if preTicks.Load() != 0 {
+		t.Fatalf("Expected no PreTickEvent without WithTickEvents, received %d\n", preTicks.Load())
+	}
+}
+
+func TestTicklessTickEvents(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TicklessTPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithTickless(time.Millisecond),
+		banji.WithTickEvents(),
+	)
+
+	preTicks := make(chan struct{}, 1)
+	banji.SubscribeFunc(eng, banji.PreTickTopic, func(e *banji.PreTickEvent) error {
+		select {
+		case preTicks <- struct{}{}:
+		default:
+		}
+
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	eng.Post(new(SteppedEvent), 0)
+
+	select {
+	case <-preTicks:
+	case <-time.After(Timeout / 2):
+		t.Fatalf("Timed out waiting for *banji.PreTickEvent\n")
+	}
+
+	// Tick events must not keep the engine awake.
+	time.Sleep(Idle)
+	ticks := eng.Ticks()
+	time.Sleep(Idle)
+
+	if eng.Ticks() != ticks {
+		t.Fatalf("Expected no ticks while idle, received %d\n", eng.Ticks()-ticks)
+	}
+}
+
+func TestTicklessScheduledPosts(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithTickless(0),
+	)
+
+	handled := make(chan struct{}, 1)
+	banji.SubscribeFunc(eng, ScheduledTopic, func(e *ScheduledEvent) error {
+		handled <- struct{}{}
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	// A post that is far from due must not keep the engine awake.
+	eng.PostAfter(new(ScheduledEvent), 0, time.Hour)
+
+	time.Sleep(Idle)
+	ticks := eng.Ticks()
+	time.Sleep(Idle)
+
+	if eng.Ticks() != ticks {
+		t.Fatalf("Expected no ticks while idle, received %d\n", eng.Ticks()-ticks)
+	}
+
+	// A post that becomes due must wake the engine up.
+	eng.PostAfter(new(ScheduledEvent), 0, Delay)
+
+	select {
+	case <-handled:
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for the scheduled post\n")
+	}
+}
+
+func TestTicklessTickRetry(t *testing.T) {
+	const (
+		tps        = 10
+		retryTicks = 3
+	)
+
+	eng := banji.New(
+		banji.WithTPS(tps),
+		banji.WithDemuxers(Demuxers),
+		banji.WithTickless(0),
+		banji.WithRetryPolicy(SteppedTopic, bus.RetryPolicy{
+			MaxAttempts: 2,
+			Backoff:     bus.FixedTickBackoff(retryTicks),
+		}),
+	)
+
+	var attempts atomic.Int64
+	retried := make(chan time.Time, 1)
+	banji.SubscribeFunc(eng, SteppedTopic, func(e *SteppedEvent) error {
+		if attempts.Add(1) == 1 {
+			return errors.New("first attempt fails")
+		}
+
+		retried <- time.Now()
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	begin := time.Now()
+	eng.Post(new(SteppedEvent), 0)
+
+	// The ticks of the backoff stand for one tick period each, which the engine must sleep through.
+	select {
+	case at := <-retried:
+		if wait := at.Sub(begin); wait < retryTicks*time.Second/tps {
+			t.Fatalf("Expected the retry to wait %d tick periods, waited %v\n", retryTicks, wait)
+		}
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for the retry\n")
+	}
+
+	if ticks := eng.Ticks(); ticks > retryTicks {
+		t.Fatalf("Expected the engine to sleep until the retry was due, ran %d ticks\n", ticks)
+	}
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+)
+
+const (
+	MetricsSchedule = "metrics"
+	MetricsTPS      = 2
+	MetricsTopic    = "test.metrics"
+)
+
+type MetricsEvent struct {
+	banji.EventEmbed
+}
+
+func (e *MetricsEvent) Topic() string {
+	return MetricsTopic
+}
+
+func TestSchedules(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+		banji.WithSchedule(MetricsSchedule, MetricsTPS, MetricsTopic),
+	)
+
+	preTicks := make(chan *banji.PreTickEvent, 1)
+	banji.SubscribeFunc(eng, banji.PreTickTopic+".>", func(e *banji.PreTickEvent) error {
+		select {
+		case preTicks <- e:
+		default:
+		}
+
+		return nil
+	})
+
+	handled := make(chan time.Time, 1)
+	banji.SubscribeFunc(eng, MetricsTopic, func(e *MetricsEvent) error {
+		handled <- time.Now()
+		return nil
+	})
+
+	eng.Start()
+	defer eng.Stop()
+
+	var tick *banji.PreTickEvent
+	select {
+	case tick = <-preTicks:
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for the PreTickEvent of %q\n", MetricsSchedule)
+	}
+
+	if tick.Schedule() != MetricsSchedule || tick.Topic() != banji.PreTickTopic+"."+MetricsSchedule {
+		t.Fatalf("Expected a PreTickEvent of %q, received one of %q on %q\n", MetricsSchedule, tick.Schedule(),
+			tick.Topic())
+	}
+
+	if tick.Timestep() != time.Second/MetricsTPS {
+		t.Fatalf("Expected a timestep of %v, received %v\n", time.Second/MetricsTPS, tick.Timestep())
+	}
+
+	// The event must wait for the next tick of its schedule rather than be handled by the main engine loop.
+	posted := time.Now()
+	eng.Post(new(MetricsEvent), 0)
+
+	select {
+	case at := <-handled:
+		if elapsed := at.Sub(posted); elapsed < Idle {
+			t.Fatalf("Expected the event to wait for the schedule, but it was handled after %v\n", elapsed)
+		}
+	case <-time.After(Timeout):
+		t.Fatalf("Timed out waiting for the scheduled event\n")
+	}
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AndrewChon/banji"
+)
+
+// tickLog records the timing of every PreTickEvent.
+type tickLog struct {
+	mu    sync.Mutex
+	ticks []*banji.PreTickEvent
+}
+
+func (l *tickLog) record(e *banji.PreTickEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.ticks = append(l.ticks, e)
+	return nil
+}
+
+func (l *tickLog) snapshot() []*banji.PreTickEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]*banji.PreTickEvent(nil), l.ticks...)
+}
+
+func TestTickTiming(t *testing.T) {
+	eng := banji.New(
+		banji.WithTPS(TPS),
+		banji.WithDemuxers(Demuxers),
+	)
+
+	log := new(tickLog)
+	banji.SubscribeFunc(eng, banji.PreTickTopic, log.record)
+
+	eng.Start()
+	time.Sleep(Idle)
+	eng.Stop()
+
+	ticks := log.snapshot()
+	if len(ticks) < 2 {
+		t.Fatalf("Expected several ticks, received %d\n", len(ticks))
+	}
+
+	for i, e := range ticks {
+		if e.Timestep() != time.Second/TPS {
+			t.Fatalf("Expected a timestep of %v, received %v\n", time.Second/TPS, e.Timestep())
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		if e.Number() != ticks[i-1].Number()+1 {
+			t.Fatalf("Expected tick %d to follow tick %d\n", e.Number(), ticks[i-1].Number())
+		}
+
+		if e.Delta() <= 0 {
+			t.Fatalf("Expected a positive delta, received %v\n", e.Delta())
+		}
+	}
+}
+
+func TestFixedTimestep(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		maxCatchUp int
+		dropped    bool
+	}{
+		{name: "CatchUp", maxCatchUp: 2 * int(Stall*TPS/time.Second)},
+		{name: "Capped", maxCatchUp: 1, dropped: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			eng := banji.New(
+				banji.WithTPS(TPS),
+				banji.WithDemuxers(Demuxers),
+				banji.WithFixedTimestep(tc.maxCatchUp),
+			)
+
+			log := new(tickLog)
+			banji.SubscribeFunc(eng, banji.PreTickTopic, log.record)
+			banji.SubscribeFunc(eng, SlowTopic, func(e *SlowEvent) error {
+				time.Sleep(Stall)
+				return nil
+			})
+
+			eng.Start()
+			eng.Post(new(SlowEvent), 0)
+			time.Sleep(Idle)
+			eng.Stop()
+
+			behind := false
+			for _, e := range log.snapshot() {
+				if e.Delta() != e.Timestep() {
+					t.Fatalf("Expected a delta of %v, received %v\n", e.Timestep(), e.Delta())
+				}
+
+				behind = behind || e.Lag() >= e.Timestep()
+			}
+
+			if !tc.dropped && !behind {
+				t.Fatalf("Expected catch-up ticks to run after the stall\n")
+			}
+
+			if dropped := eng.DroppedTicks() > 0; dropped != tc.dropped {
+				t.Fatalf("Expected dropped ticks: %v, received %d\n", tc.dropped, eng.DroppedTicks())
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package banji
+
+import (
+	"time"
+
+	"github.com/AndrewChon/banji/bus"
+)
+
+// runTickless runs the engine loop of a tickless engine, which only ticks when it is woken up, or when a retry or a
+// scheduled post is due.
+func (eng *Engine) runTickless() {
+	timer := time.NewTimer(0)
+	timer.Stop()
+	defer timer.Stop()
+
+	eng.arm(timer)
+
+	for {
+		select {
+		case <-eng.wake:
+			if !eng.batch() {
+				return
+			}
+		case <-timer.C:
+		case <-eng.rearm:
+			eng.arm(timer)
+			continue
+		case <-eng.stopLoop:
+			return
+		}
+
+		// The timer is left disarmed while the engine is paused, since Resume wakes the engine up.
+		if eng.paused.Load() {
+			continue
+		}
+
+		eng.loopWg.Add(1)
+		eng.tick(time.Now(), tickTiming{})
+		eng.loopWg.Done()
+
+		eng.arm(timer)
+	}
+}
+
+// arm arms timer for the earliest time at which a scheduled post or a retry of the main queue group is due, or stops it
+// if there is none.
+func (eng *Engine) arm(timer *time.Timer) {
+	next, ok := eng.scheduler.next()
+	if due, retrying := eng.bus.NextRetry(bus.DefaultGroup); retrying && (!ok || due.Before(next)) {
+		next, ok = due, true
+	}
+
+	if !ok {
+		timer.Stop()
+		return
+	}
+
+	timer.Reset(time.Until(next))
+}
+
+// rescheduled is called whenever a post is scheduled, so that a tickless engine can arm its timer for it.
+func (eng *Engine) rescheduled() {
+	if !eng.options.Tickless {
+		return
+	}
+
+	select {
+	case eng.rearm <- struct{}{}:
+	default:
+	}
+}
+
+// batch waits for the batch window to elapse, so that Events posted in quick succession are handled in a single tick.
+// It reports whether the engine loop should keep running.
+func (eng *Engine) batch() bool {
+	if eng.options.BatchWindow <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(eng.options.BatchWindow)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-eng.stopLoop:
+		return false
+	}
+}
+
+// notify is called by the bus whenever an Event is posted. It wakes a tickless engine up, unless the Event is a tick
+// event, which would otherwise keep the engine awake forever.
+func (eng *Engine) notify(em bus.Emittable) {
+	switch em.(type) {
+	case *PreTickEvent, *PostTickEvent:
+		return
+	}
+
+	eng.wakeUp()
+}
+
+// wakeUp wakes a tickless engine up. It has no effect on an engine that ticks at a fixed rate.
+func (eng *Engine) wakeUp() {
+	select {
+	case eng.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,161 @@
+package banji
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AndrewChon/banji/bus"
+)
+
+// A Schedule is a named tick loop that runs alongside the main engine loop at its own rate. Events posted to one of its
+// topics are only handled on its ticks, and each of its ticks is surrounded by a PreTickEvent and a PostTickEvent of
+// its own. Schedules share the workers and Receivers of the engine, and are paused, resumed and shut down along with
+// it. Step runs a tick of every schedule along with each tick of the main engine loop.
+type Schedule struct {
+	Name   string
+	TPS    int
+	Topics []string
+}
+
+// A tickSchedule runs the tick loop of a Schedule.
+type tickSchedule struct {
+	Schedule
+	ticker *time.Ticker
+	stop   chan struct{}
+
+	// mu is held for the duration of every tick of the schedule, and guards ticks and prevTick.
+	mu       sync.Mutex
+	ticks    uint64
+	prevTick time.Time
+}
+
+// newTickSchedules validates schedules and returns their tick loops along with the bus options that assign their
+// topics to queue groups of their own.
+func newTickSchedules(schedules []Schedule) ([]*tickSchedule, []bus.Option, error) {
+	var tss []*tickSchedule
+	var opts []bus.Option
+
+	seen := make(map[string]bool)
+	for _, s := range schedules {
+		if s.Name == "" || strings.ContainsAny(s.Name, bus.TopicSeparator+bus.SingleWildcard+bus.MultiWildcard) {
+			return nil, nil, fmt.Errorf("invalid schedule name %q", s.Name)
+		}
+
+		if seen[s.Name] {
+			return nil, nil, fmt.Errorf("duplicate schedule name %q", s.Name)
+		}
+
+		seen[s.Name] = true
+
+		tss = append(tss, &tickSchedule{
+			Schedule: s,
+			stop:     make(chan struct{}),
+		})
+
+		// The tick events of the schedule are handled on its own ticks.
+		patterns := append([]string{
+			scheduleTopic(PreTickTopic, s.Name),
+			scheduleTopic(PostTickTopic, s.Name),
+		}, s.Topics...)
+
+		opts = append(opts, bus.WithQueueGroup(s.Name, patterns...))
+	}
+
+	return tss, opts, nil
+}
+
+// scheduleTopic returns the topic of a tick event of the named schedule.
+func scheduleTopic(topic, schedule string) string {
+	if schedule == "" {
+		return topic
+	}
+
+	return topic + bus.TopicSeparator + schedule
+}
+
+func (s *tickSchedule) period() time.Duration {
+	return time.Second / time.Duration(s.TPS)
+}
+
+// runSchedule runs the tick loop of a schedule until it is stopped.
+func (eng *Engine) runSchedule(s *tickSchedule) {
+	for {
+		select {
+		case now := <-s.ticker.C:
+			eng.tickSchedule(s, now)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// tickSchedule runs a single tick of a schedule, unless the engine is paused.
+func (eng *Engine) tickSchedule(s *tickSchedule, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if eng.paused.Load() {
+		return
+	}
+
+	eng.runScheduleTick(s, now)
+}
+
+// runScheduleTick runs a single tick of a schedule, whether or not the engine is paused. The schedule's lock must be
+// held.
+func (eng *Engine) runScheduleTick(s *tickSchedule, now time.Time) {
+	s.ticks++
+
+	timing := tickTiming{
+		schedule: s.Name,
+		number:   s.ticks,
+		lag:      time.Since(now),
+		timestep: s.period(),
+	}
+
+	if !s.prevTick.IsZero() {
+		timing.delta = now.Sub(s.prevTick)
+	}
+
+	s.prevTick = now
+
+	eng.Post(&PreTickEvent{
+		tickTiming: timing,
+		tick:       now,
+	}, 0)
+
+	stats := eng.bus.TickGroup(s.Name)
+
+	eng.Post(&PostTickEvent{
+		tickTiming: timing,
+		tick:       now,
+		stats:      stats,
+	}, 0)
+}
+
+// startSchedules starts the tick loop of every schedule.
+func (eng *Engine) startSchedules() {
+	for _, s := range eng.schedules {
+		s.ticker = time.NewTicker(s.period())
+		go eng.runSchedule(s)
+	}
+}
+
+// stopSchedules stops the tick loop of every schedule, waiting for the tick in progress, if any, to complete.
+func (eng *Engine) stopSchedules() {
+	for _, s := range eng.schedules {
+		s.ticker.Stop()
+		s.stop <- struct{}{}
+	}
+}
+
+// tickAll ticks the main queue group and the queue group of every schedule once, without posting tick events. It is
+// used to drain the engine once its loops have stopped.
+func (eng *Engine) tickAll() {
+	eng.bus.Tick()
+	for _, s := range eng.schedules {
+		eng.bus.TickGroup(s.Name)
+	}
+}
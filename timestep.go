@@ -0,0 +1,70 @@
+package banji
+
+import (
+	"time"
+)
+
+// timestep returns the configured tick period, or zero for a tickless engine.
+func (eng *Engine) timestep() time.Duration {
+	if eng.options.Tickless {
+		return 0
+	}
+
+	return eng.period()
+}
+
+// runFixed runs the engine loop in fixed-timestep mode. Elapsed time accumulates as lag, and the loop runs one tick for
+// every timestep of lag, up to 1+MaxCatchUp ticks at a time.
+func (eng *Engine) runFixed() {
+	step := eng.period()
+	previous := time.Now()
+
+	var lag time.Duration
+	for {
+		select {
+		case <-eng.ticker.C:
+		case <-eng.stopLoop:
+			return
+		}
+
+		now := time.Now()
+		lag += now.Sub(previous)
+		previous = now
+
+		// Time spent paused is not simulated.
+		if eng.paused.Load() {
+			lag = 0
+			continue
+		}
+
+		eng.loopWg.Add(1)
+
+		for ticks := 0; lag >= step; ticks++ {
+			if ticks > eng.options.MaxCatchUp {
+				eng.dropped.Add(uint64(lag / step))
+				lag %= step
+				break
+			}
+
+			lag -= step
+
+			start := time.Now()
+			stats, ok := eng.tick(start, tickTiming{
+				delta: step,
+				lag:   lag,
+			})
+
+			// The engine was paused during catch-up, and time spent paused is not simulated.
+			if !ok {
+				lag = 0
+				break
+			}
+
+			if duration := time.Since(start); duration > step {
+				eng.postOverrun(duration, stats, 0)
+			}
+		}
+
+		eng.loopWg.Done()
+	}
+}